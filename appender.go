@@ -1,15 +1,83 @@
 package duckdb
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/marcboeker/go-duckdb/mapping"
 )
 
+// appendDefault is the type of AppendDefault.
+type appendDefault struct{}
+
+// AppendDefault appends the DEFAULT value of a column, if passed as a value to AppendRow.
+// DuckDB only supports DEFAULT expressions that it can fold to a constant, i.e., literals like 42 or 'a' || 'b',
+// and now() or current_timestamp, which are constant within a transaction.
+// It rejects volatile expressions like random(), gen_random_uuid(), or nextval('seq').
+// To use those, omit the column with NewAppenderWithColumns.
+var AppendDefault = appendDefault{}
+
+// FlushPolicy configures when an Appender flushes its appended rows to the underlying table, in addition to
+// explicit calls to Flush and Close. Each condition is disabled, if it is not positive.
+// The zero value disables automatic flushing.
+type FlushPolicy struct {
+	// Rows flushes, if at least Rows rows have been appended since the last flush.
+	Rows int
+	// Interval flushes, if at least Interval has passed since the last flush.
+	// Without Background, the appender only checks it when appending.
+	Interval time.Duration
+	// MemoryBytes flushes, if the estimated size of the rows appended since the last flush is at least MemoryBytes.
+	// The estimate includes the fixed width of each column, and the length of string and []byte values.
+	MemoryBytes int
+	// Background starts a goroutine that flushes every Interval, even if no rows are appended.
+	// It requires a positive Interval. The connection of the appender must not be used while the appender is open,
+	// as the goroutine uses it concurrently.
+	Background bool
+}
+
+// AppenderStats are the statistics of an Appender.
+type AppenderStats struct {
+	// RowsAppended is the number of appended rows.
+	RowsAppended int64
+	// ChunksFlushed is the number of data chunks passed to DuckDB.
+	ChunksFlushed int64
+	// Flushes is the number of flushes to the underlying table, including automatic flushes.
+	Flushes int64
+	// LastFlushLatency is the duration of the last flush.
+	LastFlushLatency time.Duration
+	// TotalFlushLatency is the duration of all flushes.
+	TotalFlushLatency time.Duration
+	// RowsRejected is the number of rows rejected in reject mode, see SetRejectHandler.
+	RowsRejected int64
+	// LastError is the error of the last failed flush, including background flushes.
+	LastError error
+}
+
+// RejectedRow is a row that an Appender rejected in reject mode, see SetRejectHandler.
+type RejectedRow struct {
+	// Row is the number of the row, counting the appended and the rejected rows, starting at zero.
+	Row int64
+	// Column is the index of the column whose value could not be set.
+	Column int
+	// Values are the values of the row.
+	Values []driver.Value
+	// Err is the error of the column's value.
+	Err error
+}
+
 // Appender holds the DuckDB appender. It allows efficient bulk loading into a DuckDB database.
+// Its methods are safe for concurrent use, but the rows of concurrent calls are appended in an undefined order.
 type Appender struct {
+	// mu protects the appender, e.g., against concurrent background flushes.
+	mu       sync.Mutex
 	conn     *Conn
+	catalog  string
 	schema   string
 	table    string
 	appender mapping.Appender
@@ -19,8 +87,32 @@ type Appender struct {
 	chunk DataChunk
 	// The column types of the table to append to.
 	types []mapping.LogicalType
+	// The type information of the column types.
+	typeInfos []TypeInfo
+	// The names of the columns to append to, if known.
+	columnNames []string
+	// columnIndexes maps the column names to their index. It is initialized by AppendMap.
+	columnIndexes map[string]int
 	// The number of appended rows.
 	rowCount int
+
+	// The automatic flush policy.
+	policy FlushPolicy
+	// The estimated width of a row in bytes, excluding the length of string and []byte values.
+	rowWidth int
+	// The number of rows appended since the last flush.
+	pendingRows int
+	// The estimated size of the rows appended since the last flush.
+	pendingBytes int
+	// The time of the last flush.
+	lastFlush time.Time
+	stats     AppenderStats
+	// If not nil, the appender rejects rows with invalid values, and passes them to rejectHandler.
+	rejectHandler func(RejectedRow)
+	// flusherMu protects the background flusher, which acquires mu.
+	flusherMu   sync.Mutex
+	stopFlusher chan struct{}
+	flusherDone chan struct{}
 }
 
 // NewAppenderFromConn returns a new Appender for the default catalog from a DuckDB driver connection.
@@ -30,6 +122,17 @@ func NewAppenderFromConn(driverConn driver.Conn, schema, table string) (*Appende
 
 // NewAppender returns a new Appender from a DuckDB driver connection.
 func NewAppender(driverConn driver.Conn, catalog, schema, table string) (*Appender, error) {
+	return newAppender(driverConn, catalog, schema, table, nil)
+}
+
+// NewAppenderWithColumns returns a new Appender from a DuckDB driver connection, which appends to the given columns.
+// The column names must match the names of the table's columns exactly. The omitted columns get their DEFAULT values,
+// or NULL. If columns is empty, the Appender appends to all columns.
+func NewAppenderWithColumns(driverConn driver.Conn, catalog, schema, table string, columns []string) (*Appender, error) {
+	return newAppender(driverConn, catalog, schema, table, columns)
+}
+
+func newAppender(driverConn driver.Conn, catalog, schema, table string, columns []string) (*Appender, error) {
 	conn, ok := driverConn.(*Conn)
 	if !ok {
 		return nil, getError(errInvalidCon, nil)
@@ -46,12 +149,24 @@ func NewAppender(driverConn driver.Conn, catalog, schema, table string) (*Append
 		return nil, getError(errAppenderCreation, err)
 	}
 
+	// Set the active columns.
+	for _, column := range columns {
+		if mapping.AppenderAddColumn(appender, column) == mapping.StateError {
+			err := getDuckDBError(mapping.AppenderError(appender))
+			mapping.AppenderDestroy(&appender)
+			return nil, getError(errAppenderCreation, fmt.Errorf("%w: %s", err, column))
+		}
+	}
+
 	a := &Appender{
-		conn:     conn,
-		schema:   schema,
-		table:    table,
-		appender: appender,
-		rowCount: 0,
+		conn:        conn,
+		catalog:     catalog,
+		schema:      schema,
+		table:       table,
+		appender:    appender,
+		columnNames: slices.Clone(columns),
+		rowCount:    0,
+		lastFlush:   time.Now(),
 	}
 
 	// Get the column types.
@@ -79,42 +194,127 @@ func NewAppender(driverConn driver.Conn, catalog, schema, table string) (*Append
 		return nil, getError(errAppenderCreation, err)
 	}
 
+	// Get the type information of the column types.
+	for _, t := range a.types {
+		info, err := newTypeInfoFromLogicalType(t)
+		if err != nil {
+			a.chunk.close()
+			destroyTypeSlice(a.types)
+			mapping.AppenderDestroy(&appender)
+			return nil, getError(errAppenderCreation, err)
+		}
+		a.typeInfos = append(a.typeInfos, info)
+	}
+	for i := range a.chunk.columns {
+		a.rowWidth += a.chunk.columns[i].width()
+	}
+
 	return a, nil
 }
 
+// SetFlushPolicy sets the automatic flush policy of the appender, see FlushPolicy.
+// It replaces the previous policy, and stops its background flusher.
+func (a *Appender) SetFlushPolicy(policy FlushPolicy) error {
+	if policy.Background && policy.Interval <= 0 {
+		return getError(errAPI, invalidInputError("a background flush without an interval", "a positive interval"))
+	}
+
+	a.flusherMu.Lock()
+	defer a.flusherMu.Unlock()
+	a.stopBackgroundFlush()
+
+	a.mu.Lock()
+	a.policy = policy
+	closed := a.closed
+	a.mu.Unlock()
+
+	if policy.Background && !closed {
+		a.stopFlusher = make(chan struct{})
+		a.flusherDone = make(chan struct{})
+		go a.backgroundFlush(policy.Interval, a.stopFlusher, a.flusherDone)
+	}
+	return nil
+}
+
+// SetRejectHandler enables the reject mode of the appender, if handler is not nil, and disables it otherwise.
+// In reject mode, AppendRow, AppendMap, AppendRecord, and TypedAppender.Append skip rows with a value that cannot be set,
+// e.g., because it cannot be converted to its column type. They pass each skipped row to handler,
+// and continue with the next row. To collect the rejected rows, append them to a slice in handler.
+// The handler must not call the methods of the appender.
+func (a *Appender) SetRejectHandler(handler func(RejectedRow)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rejectHandler = handler
+}
+
+// Stats returns the statistics of the appender.
+func (a *Appender) Stats() AppenderStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stats
+}
+
 // Flush the data chunks to the underlying table and clear the internal cache.
 // Does not close the appender, even if it returns an error. Unless you have a good reason to call this,
 // call Close when you are done with the appender.
 func (a *Appender) Flush() error {
-	if err := a.appendDataChunk(); err != nil {
-		return getError(errAppenderFlush, invalidatedAppenderError(err))
-	}
+	return a.FlushContext(context.Background())
+}
 
-	if mapping.AppenderFlush(a.appender) == mapping.StateError {
-		err := getDuckDBError(mapping.AppenderError(a.appender))
-		return getError(errAppenderFlush, invalidatedAppenderError(err))
+// FlushContext flushes the appender, see Flush. If ctx is done, it does not flush, and returns ctx.Err().
+// If ctx is done during the flush, it interrupts DuckDB. If DuckDB stops the flush, FlushContext returns ctx.Err(),
+// and the appender remains open: none of the flushed rows are in the table, but they remain in the appender,
+// and the next flush retries them. DuckDB only checks for interrupts at some points of a flush,
+// so it may also complete the flush. Then, FlushContext returns its result.
+func (a *Appender) FlushContext(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
 	}
-
-	return nil
+	return a.flush(ctx)
 }
 
 // Close the appender. This will flush the appender to the underlying table.
 // It is vital to call this when you are done with the appender to avoid leaking memory.
 func (a *Appender) Close() error {
+	return a.CloseContext(context.Background())
+}
+
+// CloseContext closes the appender, see Close. If ctx is done, it does not close the appender,
+// and returns ctx.Err(). If ctx is done while it flushes the remaining rows, it interrupts the flush
+// like FlushContext. If DuckDB stops the flush, CloseContext returns ctx.Err(), and the appender is closed
+// anyway, so none of the remaining rows are in the table.
+func (a *Appender) CloseContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	a.flusherMu.Lock()
+	a.stopBackgroundFlush()
+	a.flusherMu.Unlock()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	if a.closed {
 		return getError(errAppenderDoubleClose, nil)
 	}
 	a.closed = true
+	return a.close(ctx)
+}
 
-	// Append all remaining chunks.
-	errAppend := a.appendDataChunk()
-	a.chunk.close()
+func (a *Appender) close(ctx context.Context) error {
+	var errAppend, errFlush error
+	interrupted := a.withInterrupt(ctx, func() {
+		// Append all remaining chunks.
+		errAppend = a.appendDataChunk()
 
-	// We flush before closing to get a meaningful error message.
-	var errFlush error
-	if mapping.AppenderFlush(a.appender) == mapping.StateError {
-		errFlush = getDuckDBError(mapping.AppenderError(a.appender))
-	}
+		// We flush before closing to get a meaningful error message.
+		if mapping.AppenderFlush(a.appender) == mapping.StateError {
+			errFlush = getDuckDBError(mapping.AppenderError(a.appender))
+		}
+	})
+	a.chunk.close()
 
 	// Destroy all appender data and the appender.
 	destroyTypeSlice(a.types)
@@ -124,6 +324,9 @@ func (a *Appender) Close() error {
 	}
 
 	err := errors.Join(errAppend, errFlush, errClose)
+	if err != nil && interrupted {
+		return ctx.Err()
+	}
 	if err != nil {
 		return getError(invalidatedAppenderError(err), nil)
 	}
@@ -132,7 +335,28 @@ func (a *Appender) Close() error {
 }
 
 // AppendRow loads a row of values into the appender. The values are provided as separate arguments.
+// If it fails to set a value, the row is not appended, see SetRejectHandler.
 func (a *Appender) AppendRow(args ...driver.Value) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.appendRow(context.Background(), args)
+}
+
+// AppendRowContext loads a row of values into the appender, see AppendRow. If ctx is done, it does not append
+// the row, and returns ctx.Err(). If the flush policy flushes after appending the row, see SetFlushPolicy,
+// and ctx is done during that flush, it interrupts the flush like FlushContext. Then, the row remains
+// in the appender with the other rows of the flush.
+func (a *Appender) AppendRowContext(ctx context.Context, args ...driver.Value) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.appendRow(ctx, args)
+}
+
+// appendRow appends a row. The caller must hold mu.
+func (a *Appender) appendRow(ctx context.Context, args []driver.Value) error {
 	if a.closed {
 		return getError(errAppenderAppendAfterClose, nil)
 	}
@@ -142,6 +366,95 @@ func (a *Appender) AppendRow(args ...driver.Value) error {
 		return getError(errAppenderAppendRow, err)
 	}
 
+	return a.autoFlush(ctx)
+}
+
+// AppendChunk loads the rows of a data chunk into the appender, e.g., a chunk created with NewDataChunk.
+// Its columns should have the column types of the appender, see Types. Otherwise, DuckDB casts its values.
+// The rows appended with AppendRow before are loaded first. The appender does not take ownership of the chunk.
+func (a *Appender) AppendChunk(chunk *DataChunk) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return getError(errAppenderAppendChunkAfterClose, nil)
+	}
+	if len(chunk.columns) != len(a.types) {
+		return getError(errAppenderAppendChunk, columnCountError(len(chunk.columns), len(a.types)))
+	}
+
+	// Keep the order of the rows.
+	if err := a.appendDataChunk(); err != nil {
+		return getError(errAppenderAppendChunk, err)
+	}
+	if chunk.GetSize() == 0 {
+		return nil
+	}
+	if mapping.AppendDataChunk(a.appender, chunk.chunk) == mapping.StateError {
+		return getError(errAppenderAppendChunk, getDuckDBError(mapping.AppenderError(a.appender)))
+	}
+	a.stats.ChunksFlushed++
+	a.addRows(chunk.GetSize(), 0)
+
+	return a.autoFlush(context.Background())
+}
+
+// Types returns the column types of the table to append to.
+func (a *Appender) Types() []TypeInfo {
+	return slices.Clone(a.typeInfos)
+}
+
+// AppendMap loads a row into the appender, whose values are keyed by their column name.
+// Column names match case-insensitively, like in DuckDB. Omitted columns get their DEFAULT values, see AppendDefault.
+func (a *Appender) AppendMap(row map[string]any) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return getError(errAppenderAppendAfterClose, nil)
+	}
+	if err := a.initColumnIndexes(); err != nil {
+		return getError(errAppenderAppendRow, err)
+	}
+
+	args := make([]driver.Value, len(a.types))
+	for i := range args {
+		args[i] = AppendDefault
+	}
+	for name, val := range row {
+		idx, ok := a.columnIndexes[strings.ToLower(name)]
+		if !ok {
+			return getError(errAppenderAppendRow, fmt.Errorf("%w: %s", errAppenderUnknownColumn, name))
+		}
+		args[idx] = val
+	}
+
+	if err := a.appendRowSlice(args); err != nil {
+		return getError(errAppenderAppendRow, err)
+	}
+	return a.autoFlush(context.Background())
+}
+
+// initColumnIndexes initializes the column indexes. If the columns are unknown, it gets them from the table.
+func (a *Appender) initColumnIndexes() error {
+	if a.columnIndexes != nil {
+		return nil
+	}
+
+	if len(a.columnNames) == 0 {
+		var desc mapping.TableDescription
+		state := mapping.TableDescriptionCreateExt(a.conn.conn, a.catalog, a.schema, a.table, &desc)
+		defer mapping.TableDescriptionDestroy(&desc)
+		if state == mapping.StateError {
+			return errors.New(mapping.TableDescriptionError(desc))
+		}
+		for i := range a.types {
+			a.columnNames = append(a.columnNames, mapping.TableDescriptionGetColumnName(desc, mapping.IdxT(i)))
+		}
+	}
+
+	a.columnIndexes = make(map[string]int, len(a.columnNames))
+	for i, name := range a.columnNames {
+		a.columnIndexes[strings.ToLower(name)] = i
+	}
 	return nil
 }
 
@@ -159,14 +472,155 @@ func (a *Appender) appendRowSlice(args []driver.Value) error {
 	}
 
 	// Set all values.
+	size := 0
 	for i, val := range args {
-		err := a.chunk.SetValue(i, a.rowCount, val)
+		var err error
+		switch v := val.(type) {
+		case appendDefault:
+			err = a.setDefault(i, a.rowCount)
+		case string:
+			size += len(v)
+			err = a.chunk.SetValue(i, a.rowCount, val)
+		case []byte:
+			size += len(v)
+			err = a.chunk.SetValue(i, a.rowCount, val)
+		default:
+			err = a.chunk.SetValue(i, a.rowCount, val)
+		}
 		if err != nil {
-			return err
+			return a.rejectRow(args, i, err)
 		}
 	}
 	a.rowCount++
+	a.addRows(1, size)
+
+	return nil
+}
 
+// rejectRow rolls back the partially set row. In reject mode, it passes the row to the reject handler,
+// and otherwise returns err.
+func (a *Appender) rejectRow(args []driver.Value, colIdx int, err error) error {
+	for i := 0; i <= colIdx; i++ {
+		a.chunk.columns[i].setValid(mapping.IdxT(a.rowCount))
+	}
+	if a.rejectHandler == nil {
+		return err
+	}
+
+	row := a.stats.RowsAppended + a.stats.RowsRejected
+	a.stats.RowsRejected++
+	a.rejectHandler(RejectedRow{Row: row, Column: colIdx, Values: slices.Clone(args), Err: err})
+	return nil
+}
+
+// addRows adds n appended rows with size bytes of string and []byte values to the statistics.
+func (a *Appender) addRows(n int, size int) {
+	a.stats.RowsAppended += int64(n)
+	a.pendingRows += n
+	a.pendingBytes += n*a.rowWidth + size
+}
+
+// autoFlush flushes the appender, if its flush policy requires it.
+func (a *Appender) autoFlush(ctx context.Context) error {
+	p := a.policy
+	if (p.Rows > 0 && a.pendingRows >= p.Rows) ||
+		(p.MemoryBytes > 0 && a.pendingBytes >= p.MemoryBytes) ||
+		(p.Interval > 0 && a.pendingRows > 0 && time.Since(a.lastFlush) >= p.Interval) {
+		return a.flush(ctx)
+	}
+	return nil
+}
+
+func (a *Appender) flush(ctx context.Context) error {
+	start := time.Now()
+	var err error
+	interrupted := a.withInterrupt(ctx, func() {
+		err = a.appendDataChunk()
+		if err == nil && mapping.AppenderFlush(a.appender) == mapping.StateError {
+			err = getDuckDBError(mapping.AppenderError(a.appender))
+		}
+	})
+
+	a.lastFlush = time.Now()
+	latency := a.lastFlush.Sub(start)
+	a.stats.Flushes++
+	a.stats.LastFlushLatency = latency
+	a.stats.TotalFlushLatency += latency
+	if err != nil {
+		err = getError(errAppenderFlush, invalidatedAppenderError(err))
+		a.stats.LastError = err
+		if interrupted {
+			return ctx.Err()
+		}
+		return err
+	}
+	a.pendingRows = 0
+	a.pendingBytes = 0
+	return nil
+}
+
+// withInterrupt runs f, and interrupts the connection, if ctx is done before f returns.
+// It returns true, if it interrupted the connection.
+func (a *Appender) withInterrupt(ctx context.Context, f func()) bool {
+	if ctx.Done() == nil {
+		f()
+		return false
+	}
+
+	mainDoneCh := make(chan struct{})
+	bgDoneCh := make(chan bool)
+	go func() {
+		select {
+		case <-ctx.Done():
+			mapping.Interrupt(a.conn.conn)
+			bgDoneCh <- true
+		case <-mainDoneCh:
+			bgDoneCh <- false
+		}
+	}()
+
+	f()
+	close(mainDoneCh)
+	// Wait for the goroutine, so that it cannot interrupt the next statement of the connection.
+	return <-bgDoneCh
+}
+
+// backgroundFlush flushes the appender every interval, until stop is closed.
+func (a *Appender) backgroundFlush(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			a.mu.Lock()
+			if !a.closed && a.pendingRows > 0 {
+				// The error is available via Stats.
+				_ = a.flush(context.Background())
+			}
+			a.mu.Unlock()
+		}
+	}
+}
+
+// stopBackgroundFlush stops the background flusher, if it is running. The caller must hold flusherMu.
+func (a *Appender) stopBackgroundFlush() {
+	if a.stopFlusher == nil {
+		return
+	}
+	close(a.stopFlusher)
+	<-a.flusherDone
+	a.stopFlusher = nil
+	a.flusherDone = nil
+}
+
+func (a *Appender) setDefault(colIdx int, rowIdx int) error {
+	if mapping.AppendDefaultToChunk(a.appender, a.chunk.chunk, mapping.IdxT(colIdx), mapping.IdxT(rowIdx)) == mapping.StateError {
+		return addIndexToError(getDuckDBError(mapping.AppenderError(a.appender)), colIdx)
+	}
 	return nil
 }
 
@@ -181,6 +635,7 @@ func (a *Appender) appendDataChunk() error {
 	if mapping.AppendDataChunk(a.appender, a.chunk.chunk) == mapping.StateError {
 		return getDuckDBError(mapping.AppenderError(a.appender))
 	}
+	a.stats.ChunksFlushed++
 
 	a.chunk.reset(true)
 	a.rowCount = 0
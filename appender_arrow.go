@@ -0,0 +1,477 @@
+//go:build duckdb_arrow
+
+package duckdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+
+	"github.com/marcboeker/go-duckdb/mapping"
+)
+
+// AppendRecord loads the rows of an Apache Arrow record into the appender.
+// If all record fields match the appender's columns by name (case-insensitively), it maps them by name,
+// and the omitted columns get their DEFAULT values, see AppendDefault. Otherwise, the record must have
+// a field for each column, which it maps by position.
+// The field types must be convertible to the column types without loss, e.g., an arrow.INT32 field
+// to an INTEGER or BIGINT column, but not to a SMALLINT or UINTEGER column. Likewise, a DECIMAL128 field
+// must not have more fractional or integer digits than its DECIMAL column, and the nanoseconds
+// of an interval must be whole microseconds.
+// If AppendRecord fails, the rows of the record's preceding data chunks may have been appended.
+// In reject mode, see SetRejectHandler, it skips the rows with a value that cannot be set.
+func (a *Appender) AppendRecord(rec arrow.Record) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return getError(errAppenderAppendRecordAfterClose, nil)
+	}
+	if err := a.appendRecord(rec); err != nil {
+		return getError(errAppenderAppendRecord, err)
+	}
+	return a.autoFlush(context.Background())
+}
+
+// AppendRecordReader loads the rows of all records of an Apache Arrow record reader into the appender,
+// see AppendRecord.
+func (a *Appender) AppendRecordReader(rdr array.RecordReader) error {
+	for rdr.Next() {
+		if err := a.AppendRecord(rdr.Record()); err != nil {
+			return err
+		}
+	}
+	if err := rdr.Err(); err != nil {
+		return getError(errAppenderAppendRecord, err)
+	}
+	return nil
+}
+
+func (a *Appender) appendRecord(rec arrow.Record) error {
+	fields, err := a.arrowFields(rec.Schema())
+	if err != nil {
+		return err
+	}
+
+	rowCount := int(rec.NumRows())
+	for offset := 0; offset < rowCount; {
+		// Create a new data chunk if the current chunk is full.
+		if a.rowCount == GetDataChunkCapacity() {
+			if err = a.appendDataChunk(); err != nil {
+				return err
+			}
+		}
+
+		n := min(rowCount-offset, GetDataChunkCapacity()-a.rowCount)
+		if err = a.setArrowRows(rec, fields, offset, n); err != nil {
+			// Roll back the NULL values of the partially set rows.
+			for i := range a.chunk.columns {
+				for rowIdx := a.rowCount; rowIdx < a.rowCount+n; rowIdx++ {
+					a.chunk.columns[i].setValid(mapping.IdxT(rowIdx))
+				}
+			}
+			if a.rejectHandler == nil {
+				return err
+			}
+			// In reject mode, set the rows one by one, and skip the rows with invalid values.
+			if err = a.appendArrowRows(rec, fields, offset, n); err != nil {
+				return err
+			}
+			offset += n
+			continue
+		}
+		a.rowCount += n
+		a.addRows(n, 0)
+		offset += n
+	}
+	return nil
+}
+
+// setArrowRows sets n rows of the chunk, starting at the appender's row count, to the rows of the record,
+// starting at offset.
+func (a *Appender) setArrowRows(rec arrow.Record, fields []int, offset int, n int) error {
+	for colIdx, fieldIdx := range fields {
+		if fieldIdx == -1 {
+			for i := 0; i < n; i++ {
+				if err := a.setDefault(colIdx, a.rowCount+i); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		vec := &a.chunk.columns[colIdx]
+		if err := setArrowColumn(vec, rec.Column(fieldIdx), offset, a.rowCount, n); err != nil {
+			return addIndexToError(err, fieldIdx)
+		}
+	}
+	return nil
+}
+
+// appendArrowRows appends n rows of the record, starting at offset, one by one.
+// It passes the rows with invalid values to the reject handler.
+func (a *Appender) appendArrowRows(rec arrow.Record, fields []int, offset int, n int) error {
+	for i := offset; i < offset+n; i++ {
+		args := make([]driver.Value, len(fields))
+		valueErrs := make([]error, len(fields))
+		for colIdx, fieldIdx := range fields {
+			if fieldIdx == -1 {
+				args[colIdx] = AppendDefault
+			} else {
+				args[colIdx], valueErrs[colIdx] = arrowValue(rec.Column(fieldIdx), i)
+			}
+		}
+
+		rejected := false
+		for colIdx, arg := range args {
+			var err error
+			switch {
+			case valueErrs[colIdx] != nil:
+				err = valueErrs[colIdx]
+			case fields[colIdx] == -1:
+				err = a.setDefault(colIdx, a.rowCount)
+			default:
+				vec := &a.chunk.columns[colIdx]
+				err = vec.setFn(vec, mapping.IdxT(a.rowCount), arg)
+			}
+			if err != nil {
+				if err = a.rejectRow(args, colIdx, addIndexToError(err, fields[colIdx])); err != nil {
+					return err
+				}
+				rejected = true
+				break
+			}
+		}
+		if !rejected {
+			a.rowCount++
+			a.addRows(1, 0)
+		}
+	}
+	return nil
+}
+
+// arrowFields returns the index of the schema's field for each column, or -1 for columns without a field.
+func (a *Appender) arrowFields(schema *arrow.Schema) ([]int, error) {
+	fields := make([]int, len(a.types))
+	for i := range fields {
+		fields[i] = -1
+	}
+
+	byName := a.initColumnIndexes() == nil
+	for i := 0; i < schema.NumFields() && byName; i++ {
+		colIdx, ok := a.columnIndexes[strings.ToLower(schema.Field(i).Name)]
+		if !ok || fields[colIdx] != -1 {
+			byName = false
+			break
+		}
+		fields[colIdx] = i
+	}
+
+	if !byName {
+		if schema.NumFields() != len(a.types) {
+			return nil, columnCountError(schema.NumFields(), len(a.types))
+		}
+		for i := range fields {
+			fields[i] = i
+		}
+	}
+
+	for colIdx, fieldIdx := range fields {
+		if fieldIdx == -1 {
+			continue
+		}
+		if err := checkArrowType(schema.Field(fieldIdx).Type, &a.chunk.columns[colIdx]); err != nil {
+			return nil, addIndexToError(err, fieldIdx)
+		}
+	}
+	return fields, nil
+}
+
+// checkArrowType returns an error, if the values of an Arrow type cannot be converted to the vector's type.
+func checkArrowType(dt arrow.DataType, vec *vector) error {
+	ok := false
+	switch dt.ID() {
+	case arrow.NULL:
+		ok = true
+	case arrow.BOOL:
+		ok = vec.Type == TYPE_BOOLEAN
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64, arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64,
+		arrow.FLOAT32, arrow.FLOAT64:
+		ok = arrowNumericFits(dt.ID(), vec.Type)
+	case arrow.STRING, arrow.LARGE_STRING:
+		ok = vec.Type == TYPE_VARCHAR || vec.Type == TYPE_BLOB || vec.Type == TYPE_ENUM
+	case arrow.BINARY, arrow.LARGE_BINARY:
+		ok = vec.Type == TYPE_VARCHAR || vec.Type == TYPE_BLOB
+	case arrow.FIXED_SIZE_BINARY:
+		ok = vec.Type == TYPE_VARCHAR || vec.Type == TYPE_BLOB || vec.Type == TYPE_UUID
+	case arrow.DATE32, arrow.DATE64:
+		ok = vec.Type == TYPE_DATE
+	case arrow.TIMESTAMP:
+		switch vec.Type {
+		case TYPE_TIMESTAMP, TYPE_TIMESTAMP_S, TYPE_TIMESTAMP_MS, TYPE_TIMESTAMP_NS, TYPE_TIMESTAMP_TZ:
+			ok = true
+		}
+	case arrow.TIME32, arrow.TIME64:
+		ok = vec.Type == TYPE_TIME || vec.Type == TYPE_TIME_TZ
+	case arrow.DECIMAL128:
+		// The column must hold the integer and fractional digits of the Arrow type.
+		if vec.Type == TYPE_DECIMAL {
+			decimalType := dt.(*arrow.Decimal128Type)
+			ok = decimalType.Scale <= int32(vec.decimalScale) &&
+				decimalType.Precision-decimalType.Scale <= int32(vec.decimalWidth)-int32(vec.decimalScale)
+		}
+	case arrow.INTERVAL_MONTH_DAY_NANO:
+		ok = vec.Type == TYPE_INTERVAL
+	case arrow.LIST, arrow.LARGE_LIST:
+		if vec.Type == TYPE_LIST {
+			return checkArrowType(dt.(arrow.ListLikeType).Elem(), &vec.childVectors[0])
+		}
+	case arrow.FIXED_SIZE_LIST:
+		listType := dt.(*arrow.FixedSizeListType)
+		if vec.Type == TYPE_LIST || (vec.Type == TYPE_ARRAY && mapping.IdxT(listType.Len()) == vec.arrayLength) {
+			return checkArrowType(listType.Elem(), &vec.childVectors[0])
+		}
+	case arrow.MAP:
+		if vec.Type == TYPE_MAP {
+			mapType := dt.(*arrow.MapType)
+			entries := &vec.childVectors[0]
+			if err := checkArrowType(mapType.KeyType(), &entries.childVectors[0]); err != nil {
+				return err
+			}
+			return checkArrowType(mapType.ItemType(), &entries.childVectors[1])
+		}
+	case arrow.STRUCT:
+		if vec.Type == TYPE_STRUCT {
+			structType := dt.(*arrow.StructType)
+			for i, entry := range vec.structEntries {
+				fieldIdx, found := structType.FieldIdx(entry.Name())
+				if !found {
+					return structFieldError("missing field", entry.Name())
+				}
+				if err := checkArrowType(structType.Field(fieldIdx).Type, &vec.childVectors[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case arrow.DICTIONARY:
+		return checkArrowType(dt.(*arrow.DictionaryType).ValueType, vec)
+	}
+
+	if !ok {
+		return castError(dt.String(), typeToStringMap[vec.Type])
+	}
+	return nil
+}
+
+// arrowIntegerRanges are the number of bits and the signedness of the Arrow integer types.
+var arrowIntegerRanges = map[arrow.Type]struct {
+	bits   int
+	signed bool
+}{
+	arrow.INT8: {8, true}, arrow.INT16: {16, true}, arrow.INT32: {32, true}, arrow.INT64: {64, true},
+	arrow.UINT8: {8, false}, arrow.UINT16: {16, false}, arrow.UINT32: {32, false}, arrow.UINT64: {64, false},
+}
+
+// integerRanges are the number of bits and the signedness of the DuckDB integer types.
+var integerRanges = map[Type]struct {
+	bits   int
+	signed bool
+}{
+	TYPE_TINYINT: {8, true}, TYPE_SMALLINT: {16, true}, TYPE_INTEGER: {32, true}, TYPE_BIGINT: {64, true},
+	TYPE_HUGEINT:  {128, true},
+	TYPE_UTINYINT: {8, false}, TYPE_USMALLINT: {16, false}, TYPE_UINTEGER: {32, false}, TYPE_UBIGINT: {64, false},
+}
+
+// arrowNumericFits returns true, if each value of an Arrow numeric type fits the DuckDB type.
+// Integers fit wider integer types and floating-point types, and floating-point values fit wider floating-point types.
+func arrowNumericFits(id arrow.Type, t Type) bool {
+	switch t {
+	case TYPE_FLOAT:
+		return id != arrow.FLOAT64
+	case TYPE_DOUBLE:
+		return true
+	}
+
+	src, ok := arrowIntegerRanges[id]
+	if !ok {
+		return false
+	}
+	dst, ok := integerRanges[t]
+	if !ok {
+		return false
+	}
+	if src.signed {
+		return dst.signed && dst.bits >= src.bits
+	}
+	return dst.bits > src.bits || (!dst.signed && dst.bits == src.bits)
+}
+
+// setArrowColumn sets n rows of the vector, starting at rowIdx, to the values of arr, starting at offset.
+func setArrowColumn(vec *vector, arr arrow.Array, offset int, rowIdx int, n int) error {
+	// Copy primitive values without conversion, if the types match.
+	copied := false
+	switch arr := arr.(type) {
+	case *array.Int8:
+		copied = setArrowPrimitives(vec, arr, arr.Int8Values(), offset, rowIdx, n)
+	case *array.Int16:
+		copied = setArrowPrimitives(vec, arr, arr.Int16Values(), offset, rowIdx, n)
+	case *array.Int32:
+		copied = setArrowPrimitives(vec, arr, arr.Int32Values(), offset, rowIdx, n)
+	case *array.Int64:
+		copied = setArrowPrimitives(vec, arr, arr.Int64Values(), offset, rowIdx, n)
+	case *array.Uint8:
+		copied = setArrowPrimitives(vec, arr, arr.Uint8Values(), offset, rowIdx, n)
+	case *array.Uint16:
+		copied = setArrowPrimitives(vec, arr, arr.Uint16Values(), offset, rowIdx, n)
+	case *array.Uint32:
+		copied = setArrowPrimitives(vec, arr, arr.Uint32Values(), offset, rowIdx, n)
+	case *array.Uint64:
+		copied = setArrowPrimitives(vec, arr, arr.Uint64Values(), offset, rowIdx, n)
+	case *array.Float32:
+		copied = setArrowPrimitives(vec, arr, arr.Float32Values(), offset, rowIdx, n)
+	case *array.Float64:
+		copied = setArrowPrimitives(vec, arr, arr.Float64Values(), offset, rowIdx, n)
+	}
+	if copied {
+		return nil
+	}
+
+	for i := 0; i < n; i++ {
+		v, err := arrowValue(arr, offset+i)
+		if err != nil {
+			return err
+		}
+		if err = vec.setFn(vec, mapping.IdxT(rowIdx+i), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setArrowPrimitives[T fixedWidthType](vec *vector, arr arrow.Array, values []T, offset int, rowIdx int, n int) bool {
+	var zero T
+	if vec.Type != fixedWidthTypeOf(zero) {
+		return false
+	}
+
+	dst := unsafe.Slice((*T)(vec.dataPtr), rowIdx+n)
+	copy(dst[rowIdx:], values[offset:offset+n])
+	if arr.NullN() == 0 {
+		return true
+	}
+	for i := 0; i < n; i++ {
+		if arr.IsNull(offset + i) {
+			vec.setNull(mapping.IdxT(rowIdx + i))
+		}
+	}
+	return true
+}
+
+// arrowValue returns the i-th value of arr as a Go value, which the vector setters accept.
+// It fails for values that DuckDB cannot represent without loss.
+func arrowValue(arr arrow.Array, i int) (any, error) {
+	if arr.IsNull(i) {
+		return nil, nil
+	}
+
+	switch arr := arr.(type) {
+	case *array.Boolean:
+		return arr.Value(i), nil
+	case *array.Int8:
+		return arr.Value(i), nil
+	case *array.Int16:
+		return arr.Value(i), nil
+	case *array.Int32:
+		return arr.Value(i), nil
+	case *array.Int64:
+		return arr.Value(i), nil
+	case *array.Uint8:
+		return arr.Value(i), nil
+	case *array.Uint16:
+		return arr.Value(i), nil
+	case *array.Uint32:
+		return arr.Value(i), nil
+	case *array.Uint64:
+		return arr.Value(i), nil
+	case *array.Float32:
+		return arr.Value(i), nil
+	case *array.Float64:
+		return arr.Value(i), nil
+	case *array.String:
+		return arr.Value(i), nil
+	case *array.LargeString:
+		return arr.Value(i), nil
+	case *array.Binary:
+		return arr.Value(i), nil
+	case *array.LargeBinary:
+		return arr.Value(i), nil
+	case *array.FixedSizeBinary:
+		return arr.Value(i), nil
+	case *array.Date32:
+		return arr.Value(i).ToTime(), nil
+	case *array.Date64:
+		return arr.Value(i).ToTime(), nil
+	case *array.Timestamp:
+		return arr.Value(i).ToTime(arr.DataType().(*arrow.TimestampType).Unit), nil
+	case *array.Time32:
+		return arr.Value(i).ToTime(arr.DataType().(*arrow.Time32Type).Unit), nil
+	case *array.Time64:
+		return arr.Value(i).ToTime(arr.DataType().(*arrow.Time64Type).Unit), nil
+	case *array.Decimal128:
+		decimalType := arr.DataType().(*arrow.Decimal128Type)
+		return Decimal{Width: uint8(decimalType.Precision), Scale: uint8(decimalType.Scale), Value: arr.Value(i).BigInt()}, nil
+	case *array.MonthDayNanoInterval:
+		v := arr.Value(i)
+		// DuckDB intervals have a microsecond precision.
+		if v.Nanoseconds%1000 != 0 {
+			return nil, castError(fmt.Sprintf("an interval with %d nanoseconds", v.Nanoseconds), "INTERVAL")
+		}
+		return Interval{Months: v.Months, Days: v.Days, Micros: v.Nanoseconds / 1000}, nil
+	case *array.Map:
+		start, end := arr.ValueOffsets(i)
+		keys, items := arr.Keys(), arr.Items()
+		m := make(OrderedMap, 0, end-start)
+		for j := int(start); j < int(end); j++ {
+			key, err := arrowValue(keys, j)
+			if err != nil {
+				return nil, err
+			}
+			value, err := arrowValue(items, j)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, MapEntry{Key: key, Value: value})
+		}
+		return m, nil
+	case array.ListLike:
+		start, end := arr.ValueOffsets(i)
+		values := arr.ListValues()
+		list := make([]any, 0, end-start)
+		for j := int(start); j < int(end); j++ {
+			value, err := arrowValue(values, j)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	case *array.Struct:
+		structType := arr.DataType().(*arrow.StructType)
+		m := make(map[string]any, arr.NumField())
+		for j := 0; j < arr.NumField(); j++ {
+			value, err := arrowValue(arr.Field(j), i)
+			if err != nil {
+				return nil, err
+			}
+			m[structType.Field(j).Name] = value
+		}
+		return m, nil
+	case *array.Dictionary:
+		return arrowValue(arr.Dictionary(), arr.GetValueIndex(i))
+	}
+	return nil, nil
+}
@@ -0,0 +1,137 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentAppender appends rows from many goroutines to a table. It shards the rows across several connections
+// of a Connector, each with its own Appender. Each shard flushes its rows independently,
+// so the rows of different shards are not appended atomically, and their order is undefined.
+type ConcurrentAppender struct {
+	conns  []driver.Conn
+	shards []*Appender
+	// next is the shard to try first.
+	next   atomic.Uint64
+	closed atomic.Bool
+}
+
+// NewConcurrentAppender returns a new ConcurrentAppender, which appends to the table with shards connections of c.
+func NewConcurrentAppender(c *Connector, catalog, schema, table string, shards int) (*ConcurrentAppender, error) {
+	if shards < 1 {
+		return nil, getError(errAppenderCreation, invalidInputError(fmt.Sprintf("%d shards", shards), "at least one shard"))
+	}
+
+	ca := &ConcurrentAppender{}
+	for i := 0; i < shards; i++ {
+		conn, err := c.Connect(context.Background())
+		if err != nil {
+			return nil, errors.Join(getError(errAppenderCreation, err), ca.close())
+		}
+		ca.conns = append(ca.conns, conn)
+
+		a, err := NewAppender(conn, catalog, schema, table)
+		if err != nil {
+			return nil, errors.Join(err, ca.close())
+		}
+		ca.shards = append(ca.shards, a)
+	}
+	return ca, nil
+}
+
+// AppendRow loads a row of values into one of the shards, see Appender.AppendRow.
+// It prefers shards that no other goroutine is appending to.
+func (ca *ConcurrentAppender) AppendRow(args ...driver.Value) error {
+	if ca.closed.Load() {
+		return getError(errAppenderAppendAfterClose, nil)
+	}
+
+	start := int(ca.next.Add(1) % uint64(len(ca.shards)))
+	for i := range ca.shards {
+		a := ca.shards[(start+i)%len(ca.shards)]
+		if a.mu.TryLock() {
+			err := a.appendRow(context.Background(), args)
+			a.mu.Unlock()
+			return err
+		}
+	}
+
+	// All shards are busy.
+	a := ca.shards[start]
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.appendRow(context.Background(), args)
+}
+
+// SetFlushPolicy sets the flush policy of each shard, see Appender.SetFlushPolicy.
+func (ca *ConcurrentAppender) SetFlushPolicy(policy FlushPolicy) error {
+	for _, a := range ca.shards {
+		if err := a.SetFlushPolicy(policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush flushes all shards concurrently, see Appender.Flush. It returns the joined errors of the shards.
+func (ca *ConcurrentAppender) Flush() error {
+	errs := make([]error, len(ca.shards))
+	var wg sync.WaitGroup
+	for i, a := range ca.shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = a.Flush()
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Close closes all shards and their connections, see Appender.Close. It returns the joined errors of the shards.
+func (ca *ConcurrentAppender) Close() error {
+	if ca.closed.Swap(true) {
+		return getError(errAppenderDoubleClose, nil)
+	}
+	return ca.close()
+}
+
+// Stats returns the sum of the statistics of all shards, see Appender.Stats.
+// LastFlushLatency is the maximum of the shards, and LastError is the first error of a shard.
+func (ca *ConcurrentAppender) Stats() AppenderStats {
+	var stats AppenderStats
+	for _, a := range ca.shards {
+		s := a.Stats()
+		stats.RowsAppended += s.RowsAppended
+		stats.ChunksFlushed += s.ChunksFlushed
+		stats.Flushes += s.Flushes
+		stats.LastFlushLatency = max(stats.LastFlushLatency, s.LastFlushLatency)
+		stats.TotalFlushLatency += s.TotalFlushLatency
+		stats.RowsRejected += s.RowsRejected
+		if stats.LastError == nil {
+			stats.LastError = s.LastError
+		}
+	}
+	return stats
+}
+
+func (ca *ConcurrentAppender) close() error {
+	errs := make([]error, len(ca.conns))
+	var wg sync.WaitGroup
+	for i, conn := range ca.conns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i < len(ca.shards) {
+				errs[i] = ca.shards[i].Close()
+			}
+			errs[i] = errors.Join(errs[i], conn.Close())
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,84 @@
+package duckdb
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentAppender(t *testing.T) {
+	c := newConnectorWrapper(t, ``, nil)
+	defer closeConnectorWrapper(t, c)
+	db := sql.OpenDB(c)
+	defer closeDbWrapper(t, db)
+	_, err := db.Exec(`CREATE TABLE test (producer INTEGER, id INTEGER)`)
+	require.NoError(t, err)
+
+	ca, err := NewConcurrentAppender(c, "", "", "test", 4)
+	require.NoError(t, err)
+	require.NoError(t, ca.SetFlushPolicy(FlushPolicy{Rows: 500}))
+
+	const producers = 16
+	const rowCount = 1000
+	// The producers report their first error on the test goroutine.
+	errs := make(chan error, producers)
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rowCount; i++ {
+				if err := ca.AppendRow(int32(p), int32(i)); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, ca.Flush())
+	require.Equal(t, int64(producers*rowCount), ca.Stats().RowsAppended)
+	require.NoError(t, ca.Close())
+
+	var count, distinct int
+	err = db.QueryRow(`SELECT count(*), count(DISTINCT (producer, id)) FROM test`).Scan(&count, &distinct)
+	require.NoError(t, err)
+	require.Equal(t, producers*rowCount, count)
+	require.Equal(t, producers*rowCount, distinct)
+
+	err = ca.AppendRow(int32(0), int32(0))
+	testError(t, err, errAppenderAppendAfterClose.Error())
+	err = ca.Close()
+	testError(t, err, errAppenderDoubleClose.Error())
+}
+
+func TestConcurrentAppenderErrors(t *testing.T) {
+	c := newConnectorWrapper(t, ``, nil)
+	defer closeConnectorWrapper(t, c)
+	db := sql.OpenDB(c)
+	defer closeDbWrapper(t, db)
+	_, err := db.Exec(`CREATE TABLE test (id INTEGER PRIMARY KEY)`)
+	require.NoError(t, err)
+
+	_, err = NewConcurrentAppender(c, "", "", "test", 0)
+	testError(t, err, errAppenderCreation.Error(), invalidInputErrMsg)
+
+	_, err = NewConcurrentAppender(c, "", "", "does_not_exist", 2)
+	testError(t, err, errAppenderCreation.Error())
+
+	// The shards report their errors.
+	ca, err := NewConcurrentAppender(c, "", "", "test", 2)
+	require.NoError(t, err)
+	require.NoError(t, ca.AppendRow(int32(1)))
+	require.NoError(t, ca.AppendRow(int32(1)))
+	err = ca.Flush()
+	testError(t, err, errAppenderFlush.Error())
+	require.Error(t, ca.Close())
+}
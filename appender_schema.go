@@ -0,0 +1,319 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"maps"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marcboeker/go-duckdb/mapping"
+)
+
+// TypedAppenderOptions configures the table of NewTypedAppenderWithOptions.
+type TypedAppenderOptions struct {
+	// CreateTable creates the table from the fields of T, if it does not exist.
+	CreateTable bool
+	// AddColumns adds a nullable column to the table for each field of T without a column.
+	AddColumns bool
+}
+
+// NewTypedAppenderWithOptions returns a new TypedAppender for the default catalog from a DuckDB driver connection,
+// see NewTypedAppender. Depending on opts, it creates the table, or adds the missing columns, before.
+// The column types are derived from the field types, see EnsureTable.
+func NewTypedAppenderWithOptions[T any](driverConn driver.Conn, schema, table string, opts TypedAppenderOptions) (*TypedAppender[T], error) {
+	if opts.CreateTable || opts.AddColumns {
+		t := reflect.TypeFor[T]()
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, getError(errAppenderCreation, castError(reflect.TypeFor[T]().String(), reflect.Struct.String()))
+		}
+		columns, err := structColumns(t)
+		if err == nil {
+			err = ensureTable(driverConn, "", schema, table, columns, opts)
+		}
+		if err != nil {
+			return nil, getError(errAppenderCreation, err)
+		}
+	}
+	return NewTypedAppender[T](driverConn, schema, table)
+}
+
+// EnsureTable creates a table from a sample row, if it does not exist, and adds a nullable column
+// for each value of the sample row without a column. The sample row is a struct, a pointer to a struct,
+// or a map[string]any. Struct fields map to columns like in NewTypedAppender.
+// The column types are derived from the Go types, e.g., BIGINT for int and int64, VARCHAR for string,
+// TIMESTAMP for time.Time, INTERVAL for time.Duration, LIST for slices, and STRUCT for structs.
+// DECIMAL values keep their width and scale. A Decimal field has no width and scale, so it is DECIMAL(18,3),
+// unless its tag sets the type, e.g., `duckdb:"amount,type=DECIMAL(38,10)"`.
+// Go arrays are ARRAY columns, e.g., UTINYINT[16] for [16]byte, so use the UUID type for UUID columns.
+// For other column types, create the table before.
+// The map values that are nil are skipped.
+func EnsureTable(driverConn driver.Conn, catalog, schema, table string, sample any) error {
+	var columns []columnDef
+	var err error
+	if m, ok := sample.(map[string]any); ok {
+		columns, err = mapColumns(m)
+	} else {
+		v := reflect.ValueOf(sample)
+		if v.Kind() == reflect.Pointer && !v.IsNil() {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return getError(errEnsureTable, castError(fmt.Sprintf("%T", sample), reflect.Struct.String()))
+		}
+		columns, err = structColumns(v.Type())
+	}
+	if err == nil {
+		err = ensureTable(driverConn, catalog, schema, table, columns, TypedAppenderOptions{CreateTable: true, AddColumns: true})
+	}
+	if err != nil {
+		return getError(errEnsureTable, err)
+	}
+	return nil
+}
+
+// columnDef is the definition of a column.
+type columnDef struct {
+	name string
+	info TypeInfo
+}
+
+func (def columnDef) sql() string {
+	logicalType := def.info.logicalType()
+	defer mapping.DestroyLogicalType(&logicalType)
+	return quoteIdentifiers(def.name) + " " + logicalTypeName(logicalType)
+}
+
+func structColumns(t reflect.Type) ([]columnDef, error) {
+	fields := structFields(t)
+	if len(fields) == 0 {
+		return nil, structFieldError("no exported fields", "at least one field")
+	}
+	columns := make([]columnDef, len(fields))
+	for i, f := range fields {
+		var info TypeInfo
+		var err error
+		if f.columnType != "" {
+			info, err = tagTypeInfo(f.fieldType, f.columnType)
+		} else {
+			info, err = goTypeInfo(f.fieldType)
+		}
+		if err != nil {
+			return nil, decodeError(f.goName, err)
+		}
+		columns[i] = columnDef{name: f.name, info: info}
+	}
+	return columns, nil
+}
+
+func mapColumns(m map[string]any) ([]columnDef, error) {
+	var columns []columnDef
+	for _, name := range slices.Sorted(maps.Keys(m)) {
+		if m[name] == nil {
+			continue
+		}
+		var info TypeInfo
+		var err error
+		if d, ok := m[name].(Decimal); ok && d.Width != 0 {
+			info, err = NewDecimalInfo(d.Width, d.Scale)
+		} else {
+			info, err = goTypeInfo(reflect.TypeOf(m[name]))
+		}
+		if err != nil {
+			return nil, decodeError(name, err)
+		}
+		columns = append(columns, columnDef{name: name, info: info})
+	}
+	if len(columns) == 0 {
+		return nil, invalidInputError("no values", "at least one value")
+	}
+	return columns, nil
+}
+
+// ensureTable creates the table, or adds the missing columns, depending on opts.
+func ensureTable(driverConn driver.Conn, catalog, schema, table string, columns []columnDef, opts TypedAppenderOptions) error {
+	conn, ok := driverConn.(*Conn)
+	if !ok {
+		return errInvalidCon
+	}
+	name := quoteIdentifiers(catalog, schema, table)
+
+	exists, err := tableExists(conn, catalog, schema, table)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if !opts.CreateTable {
+			// The caller reports that the table does not exist.
+			return nil
+		}
+		defs := make([]string, len(columns))
+		for i, def := range columns {
+			defs[i] = def.sql()
+		}
+		// Without IF NOT EXISTS, a view with the same name fails instead of being mistaken for the table.
+		query := fmt.Sprintf(`CREATE TABLE %s (%s)`, name, strings.Join(defs, ", "))
+		_, err = conn.ExecContext(context.Background(), query, nil)
+		return err
+	}
+
+	// Get the existing columns with an appender.
+	a, err := NewAppender(driverConn, catalog, schema, table)
+	if err != nil {
+		return err
+	}
+	err = a.initColumnIndexes()
+	columnIndexes := a.columnIndexes
+	if errClose := a.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil || !opts.AddColumns {
+		return err
+	}
+
+	for _, def := range columns {
+		if _, ok := columnIndexes[strings.ToLower(def.name)]; ok {
+			continue
+		}
+		query := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s`, name, def.sql())
+		if _, err = conn.ExecContext(context.Background(), query, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tableExists returns true, if a table with the name exists. Views do not count as tables.
+// An empty catalog or schema is the current one.
+func tableExists(conn *Conn, catalog, schema, table string) (bool, error) {
+	query := `SELECT count(*) FROM information_schema.tables
+		WHERE lower(table_catalog) = lower(coalesce(nullif($1, ''), current_database()))
+		AND lower(table_schema) = lower(coalesce(nullif($2, ''), current_schema()))
+		AND lower(table_name) = lower($3)
+		AND table_type IN ('BASE TABLE', 'LOCAL TEMPORARY')`
+	r, err := conn.QueryContext(context.Background(), query, []driver.NamedValue{
+		{Ordinal: 1, Value: catalog},
+		{Ordinal: 2, Value: schema},
+		{Ordinal: 3, Value: table},
+	})
+	if err != nil {
+		return false, err
+	}
+	dest := make([]driver.Value, 1)
+	err = r.Next(dest)
+	if errClose := r.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return false, err
+	}
+	return dest[0].(int64) != 0, nil
+}
+
+// tagTypeInfo returns the TypeInfo of the `type` tag option of a field. It only sets the width and scale
+// of Decimal fields.
+func tagTypeInfo(t reflect.Type, columnType string) (TypeInfo, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != reflect.TypeFor[Decimal]() {
+		return nil, castError(t.String(), reflect.TypeFor[Decimal]().String())
+	}
+	var width, scale uint8
+	typeName := strings.ToUpper(strings.ReplaceAll(columnType, " ", ""))
+	_, err := fmt.Sscanf(typeName, "DECIMAL(%d,%d)", &width, &scale)
+	if err != nil || typeName != fmt.Sprintf("DECIMAL(%d,%d)", width, scale) {
+		return nil, invalidInputError(columnType, "DECIMAL(width,scale)")
+	}
+	return NewDecimalInfo(width, scale)
+}
+
+// goTypeInfo returns the TypeInfo of the DuckDB type of a Go type.
+func goTypeInfo(t reflect.Type) (TypeInfo, error) {
+	switch t {
+	case reflect.TypeFor[*big.Int]():
+		return NewTypeInfo(TYPE_HUGEINT)
+	case reflect.TypeFor[time.Time]():
+		return NewTypeInfo(TYPE_TIMESTAMP)
+	case reflect.TypeFor[time.Duration](), reflect.TypeFor[Interval]():
+		return NewTypeInfo(TYPE_INTERVAL)
+	case reflect.TypeFor[Decimal]():
+		return NewDecimalInfo(18, 3)
+	case reflect.TypeFor[UUID]():
+		return NewTypeInfo(TYPE_UUID)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return goTypeInfo(t.Elem())
+	case reflect.Bool:
+		return NewTypeInfo(TYPE_BOOLEAN)
+	case reflect.Int8:
+		return NewTypeInfo(TYPE_TINYINT)
+	case reflect.Int16:
+		return NewTypeInfo(TYPE_SMALLINT)
+	case reflect.Int32:
+		return NewTypeInfo(TYPE_INTEGER)
+	case reflect.Int, reflect.Int64:
+		return NewTypeInfo(TYPE_BIGINT)
+	case reflect.Uint8:
+		return NewTypeInfo(TYPE_UTINYINT)
+	case reflect.Uint16:
+		return NewTypeInfo(TYPE_USMALLINT)
+	case reflect.Uint32:
+		return NewTypeInfo(TYPE_UINTEGER)
+	case reflect.Uint, reflect.Uint64:
+		return NewTypeInfo(TYPE_UBIGINT)
+	case reflect.Float32:
+		return NewTypeInfo(TYPE_FLOAT)
+	case reflect.Float64:
+		return NewTypeInfo(TYPE_DOUBLE)
+	case reflect.String:
+		return NewTypeInfo(TYPE_VARCHAR)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return NewTypeInfo(TYPE_BLOB)
+		}
+		child, err := goTypeInfo(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return NewListInfo(child)
+	case reflect.Array:
+		child, err := goTypeInfo(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return NewArrayInfo(child, uint64(t.Len()))
+	case reflect.Map:
+		key, err := goTypeInfo(t.Key())
+		if err != nil {
+			return nil, err
+		}
+		value, err := goTypeInfo(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return NewMapInfo(key, value)
+	case reflect.Struct:
+		columns, err := structColumns(t)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]StructEntry, len(columns))
+		for i, def := range columns {
+			if entries[i], err = NewStructEntry(def.info, def.name); err != nil {
+				return nil, err
+			}
+		}
+		return NewStructInfo(entries[0], entries[1:]...)
+	}
+	return nil, unsupportedTypeError(t.String())
+}
@@ -0,0 +1,171 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type schemaEventV1 struct {
+	ID      int64 `duckdb:"id"`
+	Name    string
+	Created time.Time
+	Tags    []string
+	Point   typedAppenderPoint
+	Counts  map[string]int32
+	Elapsed time.Duration
+	Price   Decimal
+	Flag    *bool
+}
+
+type schemaEventV2 struct {
+	ID     int64 `duckdb:"id"`
+	Name   string
+	Source string
+	Scores [3]float32
+}
+
+func TestTypedAppenderWithOptions(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	created := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	err := conn.Raw(func(driverConn any) error {
+		dc := driverConn.(driver.Conn)
+
+		// The table does not exist.
+		_, innerErr := NewTypedAppenderWithOptions[schemaEventV1](dc, "", "events", TypedAppenderOptions{AddColumns: true})
+		testError(t, innerErr, errAppenderCreation.Error())
+
+		a, innerErr := NewTypedAppenderWithOptions[schemaEventV1](dc, "", "events", TypedAppenderOptions{CreateTable: true})
+		require.NoError(t, innerErr)
+		require.NoError(t, a.Append(schemaEventV1{
+			ID:      1,
+			Name:    "a",
+			Created: created,
+			Tags:    []string{"x"},
+			Point:   typedAppenderPoint{X: 1, Y: 2},
+			Counts:  map[string]int32{"k": 1},
+			Elapsed: time.Minute,
+			Price:   Decimal{Width: 18, Scale: 3, Value: big.NewInt(1500)},
+		}))
+		require.NoError(t, a.Close())
+
+		// A new version adds columns.
+		_, innerErr = NewTypedAppenderWithOptions[schemaEventV2](dc, "", "events", TypedAppenderOptions{CreateTable: true})
+		testError(t, innerErr, errAppenderCreation.Error(), errAppenderUnknownColumn.Error())
+
+		b, innerErr := NewTypedAppenderWithOptions[schemaEventV2](dc, "", "events", TypedAppenderOptions{AddColumns: true})
+		require.NoError(t, innerErr)
+		require.NoError(t, b.Append(schemaEventV2{ID: 2, Name: "b", Source: "api", Scores: [3]float32{1, 2, 3}}))
+		return b.Close()
+	})
+	require.NoError(t, err)
+
+	rows, err := db.Query(`SELECT column_name, data_type FROM duckdb_columns() WHERE table_name = 'events' ORDER BY column_index`)
+	require.NoError(t, err)
+	defer closeRowsWrapper(t, rows)
+	var columns [][2]string
+	for rows.Next() {
+		var c [2]string
+		require.NoError(t, rows.Scan(&c[0], &c[1]))
+		columns = append(columns, c)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, [][2]string{
+		{"id", "BIGINT"},
+		{"Name", "VARCHAR"},
+		{"Created", "TIMESTAMP"},
+		{"Tags", "VARCHAR[]"},
+		{"Point", "STRUCT(X INTEGER, Y INTEGER)"},
+		{"Counts", "MAP(VARCHAR, INTEGER)"},
+		{"Elapsed", "INTERVAL"},
+		{"Price", "DECIMAL(18,3)"},
+		{"Flag", "BOOLEAN"},
+		{"Source", "VARCHAR"},
+		{"Scores", "FLOAT[3]"},
+	}, columns)
+
+	var name string
+	var ts time.Time
+	var source *string
+	require.NoError(t, db.QueryRow(`SELECT Name, Created, Source FROM events WHERE id = 1`).Scan(&name, &ts, &source))
+	require.Equal(t, "a", name)
+	require.Equal(t, created, ts)
+	require.Nil(t, source)
+
+	var scores string
+	require.NoError(t, db.QueryRow(`SELECT Scores::VARCHAR FROM events WHERE id = 2`).Scan(&scores))
+	require.Equal(t, "[1.0, 2.0, 3.0]", scores)
+}
+
+func TestEnsureTable(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	err := conn.Raw(func(driverConn any) error {
+		dc := driverConn.(driver.Conn)
+
+		require.NoError(t, EnsureTable(dc, "", "", "events", map[string]any{
+			"id":    int64(1),
+			"price": Decimal{Width: 10, Scale: 2, Value: big.NewInt(1)},
+			"skip":  nil,
+		}))
+		require.NoError(t, EnsureTable(dc, "", "", "events", &struct {
+			ID   int64 `duckdb:"id"`
+			Kind string
+		}{}))
+		require.NoError(t, EnsureTable(dc, "", "", "events", map[string]any{"ID": int32(1)}))
+		require.NoError(t, EnsureTable(dc, "", "", "events", struct {
+			Amount *Decimal `duckdb:"amount,type=decimal(38, 10)"`
+		}{}))
+
+		// Temporary tables exist, too.
+		_, innerErr := dc.(*Conn).ExecContext(context.Background(), `CREATE TEMP TABLE temp_events (id BIGINT)`, nil)
+		require.NoError(t, innerErr)
+		require.NoError(t, EnsureTable(dc, "temp", "", "temp_events", map[string]any{"id": int64(1), "kind": "x"}))
+
+		innerErr = EnsureTable(dc, "", "", "events", 42)
+		testError(t, innerErr, errEnsureTable.Error(), castErrMsg)
+		innerErr = EnsureTable(dc, "", "", "events", map[string]any{"values": []any{1}})
+		testError(t, innerErr, errEnsureTable.Error(), unsupportedTypeErrMsg)
+		innerErr = EnsureTable(dc, "", "", "events", map[string]any{"skip": nil})
+		testError(t, innerErr, errEnsureTable.Error(), invalidInputErrMsg)
+		innerErr = EnsureTable(dc, "", "", "events", struct {
+			Amount float64 `duckdb:"amount,type=DECIMAL(38,10)"`
+		}{})
+		testError(t, innerErr, errEnsureTable.Error(), castErrMsg)
+		innerErr = EnsureTable(dc, "", "", "events", struct {
+			Amount Decimal `duckdb:"amount,type=VARCHAR"`
+		}{})
+		testError(t, innerErr, errEnsureTable.Error(), invalidInputErrMsg)
+
+		// A view is not a table, so creating the table fails.
+		_, innerErr = dc.(*Conn).ExecContext(context.Background(), `CREATE VIEW events_view AS SELECT 1 AS id`, nil)
+		require.NoError(t, innerErr)
+		innerErr = EnsureTable(dc, "", "", "events_view", map[string]any{"id": int64(1)})
+		require.ErrorContains(t, innerErr, errEnsureTable.Error())
+		require.ErrorContains(t, innerErr, "Catalog Error")
+		innerErr = EnsureTable(dc, "missing", "", "events", map[string]any{"id": int64(1)})
+		require.ErrorContains(t, innerErr, errEnsureTable.Error())
+		return nil
+	})
+	require.NoError(t, err)
+
+	var columns string
+	err = db.QueryRow(`SELECT string_agg(column_name || ' ' || data_type, ', ' ORDER BY column_index) FROM duckdb_columns() WHERE table_name = 'events'`).Scan(&columns)
+	require.NoError(t, err)
+	require.Equal(t, "id BIGINT, price DECIMAL(10,2), Kind VARCHAR, amount DECIMAL(38,10)", columns)
+
+	err = conn.QueryRowContext(context.Background(), `SELECT string_agg(column_name, ', ' ORDER BY column_index) FROM duckdb_columns() WHERE table_name = 'temp_events'`).Scan(&columns)
+	require.NoError(t, err)
+	require.Equal(t, "id, kind", columns)
+}
@@ -10,6 +10,7 @@ import (
 	"math/rand"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -729,6 +730,40 @@ func TestAppenderDecimal(t *testing.T) {
 	require.Equal(t, 3, i)
 }
 
+func TestAppenderOrderedMap(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (id INT, m MAP(INT[], VARCHAR), typed MAP(VARCHAR, INT))`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	m := OrderedMap{{Key: []int32{3}, Value: "c"}, {Key: []int32{1, 2}, Value: nil}}
+	require.NoError(t, a.AppendRow(int32(1), m, TypedMap[string, int32]{"a": 1}))
+	require.NoError(t, a.AppendRow(int32(2), OrderedMap{}, map[string]int32{"b": 2}))
+	require.NoError(t, a.AppendRow(int32(3), nil, nil))
+	require.NoError(t, a.Flush())
+
+	res, err := db.QueryContext(context.Background(), `SELECT m, typed FROM test ORDER BY id`)
+	require.NoError(t, err)
+	defer closeRowsWrapper(t, res)
+
+	expected := []struct {
+		m     any
+		typed any
+	}{
+		{OrderedMap{{Key: []any{int32(3)}, Value: "c"}, {Key: []any{int32(1), int32(2)}, Value: nil}}, Map{"a": int32(1)}},
+		{OrderedMap{}, Map{"b": int32(2)}},
+		{nil, nil},
+	}
+
+	i := 0
+	for res.Next() {
+		var actualM, actualTyped any
+		require.NoError(t, res.Scan(&actualM, &actualTyped))
+		require.Equal(t, expected[i].m, actualM)
+		require.Equal(t, expected[i].typed, actualTyped)
+		i++
+	}
+	require.Equal(t, len(expected), i)
+}
+
 func TestAppenderStrings(t *testing.T) {
 	c, db, conn, a := prepareAppender(t, `
 	CREATE TABLE test (str VARCHAR)`)
@@ -943,6 +978,241 @@ func TestAppenderAppendDataChunk(t *testing.T) {
 	require.NoError(t, a.Flush())
 }
 
+func TestAppenderTypes(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TYPE mood AS ENUM ('happy', 'sad');
+		CREATE TABLE test (
+			i INTEGER,
+			d DECIMAL(9, 2),
+			m mood,
+			l VARCHAR[],
+			a INTEGER[3],
+			s STRUCT(x DOUBLE, y MAP(VARCHAR, BIGINT)),
+			u UNION(n INTEGER, s VARCHAR)
+		)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	types := a.Types()
+	expected := []Type{TYPE_INTEGER, TYPE_DECIMAL, TYPE_ENUM, TYPE_LIST, TYPE_ARRAY, TYPE_STRUCT, TYPE_UNION}
+	require.Len(t, types, len(expected))
+	for i, info := range types {
+		require.Equal(t, expected[i], info.InternalType())
+	}
+
+	// Append a chunk with the column types of the appender.
+	chunk, err := NewDataChunk(types)
+	require.NoError(t, err)
+	defer chunk.Close()
+
+	values := []any{
+		int32(1),
+		Decimal{Width: 9, Scale: 2, Value: big.NewInt(150)},
+		"sad",
+		[]any{"a"},
+		[]any{int32(1), int32(2), int32(3)},
+		map[string]any{"x": 1.5, "y": Map{"k": int64(1)}},
+		Union{Tag: "s", Value: "v"},
+	}
+	for i, v := range values {
+		require.NoError(t, chunk.SetValue(i, 0, v))
+	}
+	require.NoError(t, chunk.SetSize(1))
+	require.NoError(t, a.AppendChunk(chunk))
+	require.NoError(t, a.Flush())
+
+	var d Decimal
+	var m string
+	err = db.QueryRow(`SELECT d, m FROM test`).Scan(&d, &m)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 9, Scale: 2, Value: big.NewInt(150)}, d)
+	require.Equal(t, "sad", m)
+}
+
+func TestAppenderAppendChunk(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (id BIGINT, name VARCHAR, tags VARCHAR[])`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	chunk, err := NewDataChunk(a.Types())
+	require.NoError(t, err)
+	defer chunk.Close()
+	require.Equal(t, 0, chunk.GetSize())
+
+	require.NoError(t, a.AppendRow(int64(0), "first", []string{}))
+
+	const chunkCount = 3
+	size := GetDataChunkCapacity()
+	id := int64(1)
+	for range chunkCount {
+		ids := make([]int64, size)
+		names := make([]string, size)
+		nulls := NewValidity(size)
+		for i := range size {
+			ids[i] = id
+			names[i] = fmt.Sprintf("name%d", id)
+			if id%10 == 0 {
+				nulls.SetNull(i)
+			}
+			require.NoError(t, chunk.SetValue(2, i, []string{names[i]}))
+			id++
+		}
+		require.NoError(t, SetColumn(*chunk, 0, ids, Validity{}))
+		require.NoError(t, SetColumn(*chunk, 1, names, nulls))
+		require.NoError(t, chunk.SetSize(size))
+
+		require.NoError(t, a.AppendChunk(chunk))
+		chunk.Reset()
+		require.Equal(t, 0, chunk.GetSize())
+	}
+
+	require.NoError(t, a.AppendRow(id, "last", nil))
+	require.NoError(t, a.AppendChunk(chunk))
+	require.NoError(t, a.Flush())
+
+	var count, nullCount, minID, maxID int64
+	var firstName, lastName string
+	err = db.QueryRow(`SELECT count(*), count(*) FILTER (name IS NULL), min(id), max(id),
+		min_by(name, id), max_by(name, id) FROM test`).Scan(&count, &nullCount, &minID, &maxID, &firstName, &lastName)
+	require.NoError(t, err)
+	require.Equal(t, int64(size*chunkCount+2), count)
+	require.Equal(t, int64(size*chunkCount/10), nullCount)
+	require.Equal(t, int64(0), minID)
+	require.Equal(t, id, maxID)
+	require.Equal(t, "first", firstName)
+	require.Equal(t, "last", lastName)
+
+	var name string
+	var tags []any
+	err = db.QueryRow(`SELECT name, tags FROM test WHERE id = 2049`).Scan(&name, &tags)
+	require.NoError(t, err)
+	require.Equal(t, "name2049", name)
+	require.Equal(t, []any{"name2049"}, tags)
+}
+
+func TestAppenderAppendChunkErrors(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (i INTEGER)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	bigintInfo, err := NewTypeInfo(TYPE_BIGINT)
+	require.NoError(t, err)
+
+	chunk, err := NewDataChunk([]TypeInfo{bigintInfo, bigintInfo})
+	require.NoError(t, err)
+	defer chunk.Close()
+	err = a.AppendChunk(chunk)
+	testError(t, err, errAppenderAppendChunk.Error(), columnCountErrMsg)
+
+	varcharInfo, err := NewTypeInfo(TYPE_VARCHAR)
+	require.NoError(t, err)
+	mismatch, err := NewDataChunk([]TypeInfo{varcharInfo})
+	require.NoError(t, err)
+	defer mismatch.Close()
+	require.NoError(t, SetColumn(*mismatch, 0, []string{"x"}, Validity{}))
+	require.NoError(t, mismatch.SetSize(1))
+	err = a.AppendChunk(mismatch)
+	testError(t, err, errAppenderAppendChunk.Error())
+
+	_, err = NewDataChunk(nil)
+	testError(t, err, errAPI.Error(), columnCountErrMsg)
+	_, err = NewDataChunk([]TypeInfo{nil})
+	testError(t, err, errAPI.Error(), interfaceIsNilErrMsg)
+}
+
+const createDefaultsTableSQL = `
+	CREATE SEQUENCE seq;
+	CREATE TABLE test (
+		id BIGINT DEFAULT nextval('seq'),
+		created TIMESTAMP DEFAULT now(),
+		kind VARCHAR DEFAULT 'event',
+		payload VARCHAR
+	)`
+
+func TestAppenderWithColumns(t *testing.T) {
+	c := newConnectorWrapper(t, ``, nil)
+	defer closeConnectorWrapper(t, c)
+	db := sql.OpenDB(c)
+	defer closeDbWrapper(t, db)
+	_, err := db.Exec(createDefaultsTableSQL)
+	require.NoError(t, err)
+
+	conn := openDriverConnWrapper(t, c)
+	defer closeDriverConnWrapper(t, &conn)
+
+	a, err := NewAppenderWithColumns(conn, "", "", "test", []string{"payload", "kind"})
+	require.NoError(t, err)
+	types := a.Types()
+	require.Len(t, types, 2)
+	require.Equal(t, TYPE_VARCHAR, types[0].InternalType())
+
+	require.NoError(t, a.AppendRow("a", "click"))
+	require.NoError(t, a.AppendRow("b", AppendDefault))
+	require.NoError(t, a.AppendMap(map[string]any{"Payload": "c"}))
+	require.NoError(t, a.AppendMap(map[string]any{"payload": nil, "kind": "view"}))
+	err = a.AppendMap(map[string]any{"id": int64(1)})
+	testError(t, err, errAppenderAppendRow.Error(), errAppenderUnknownColumn.Error())
+	require.NoError(t, a.Close())
+
+	res, err := db.Query(`SELECT id, created IS NOT NULL, kind, payload FROM test ORDER BY id`)
+	require.NoError(t, err)
+	defer closeRowsWrapper(t, res)
+
+	type row struct {
+		id      int64
+		created bool
+		kind    string
+		payload *string
+	}
+	var rows []row
+	for res.Next() {
+		var r row
+		require.NoError(t, res.Scan(&r.id, &r.created, &r.kind, &r.payload))
+		rows = append(rows, r)
+	}
+	require.NoError(t, res.Err())
+
+	a1, b, c1 := "a", "b", "c"
+	require.Equal(t, []row{
+		{1, true, "click", &a1},
+		{2, true, "event", &b},
+		{3, true, "event", &c1},
+		{4, true, "view", nil},
+	}, rows)
+}
+
+func TestAppenderAppendDefault(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, createDefaultsTableSQL)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	// DuckDB does not support volatile DEFAULT values like nextval('seq') in the appender.
+	err := a.AppendRow(AppendDefault, AppendDefault, AppendDefault, "x")
+	testError(t, err, errAppenderAppendRow.Error(), "AppendDefault is not supported")
+
+	require.NoError(t, a.AppendRow(int64(10), AppendDefault, AppendDefault, "x"))
+	require.NoError(t, a.AppendMap(map[string]any{"id": int64(11), "kind": "custom"}))
+	require.NoError(t, a.Flush())
+
+	var count int
+	var kinds string
+	err = db.QueryRow(`SELECT count(*) FILTER (created IS NOT NULL), string_agg(kind || ':' || coalesce(payload, 'NULL'), ',' ORDER BY id) FROM test`).Scan(&count, &kinds)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, "event:x,custom:NULL", kinds)
+}
+
+func TestErrAppenderWithColumns(t *testing.T) {
+	c := newConnectorWrapper(t, ``, nil)
+	defer closeConnectorWrapper(t, c)
+	db := sql.OpenDB(c)
+	defer closeDbWrapper(t, db)
+	_, err := db.Exec(`CREATE TABLE test (i INTEGER)`)
+	require.NoError(t, err)
+
+	conn := openDriverConnWrapper(t, c)
+	defer closeDriverConnWrapper(t, &conn)
+
+	a, err := NewAppenderWithColumns(conn, "", "", "test", []string{"does_not_exist"})
+	require.Nil(t, a)
+	testError(t, err, errAppenderCreation.Error(), "does_not_exist")
+}
+
 func BenchmarkAppenderNested(b *testing.B) {
 	c, db, conn, a := prepareAppender(b, createNestedDataTableSQL)
 	defer cleanupAppender(b, c, db, conn, a)
@@ -1054,3 +1324,183 @@ func appendNestedData[T require.TestingT](t T, a *Appender, rowsToAppend []neste
 	}
 	require.NoError(t, a.Flush())
 }
+
+func TestAppenderFlushPolicy(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (id INTEGER, s VARCHAR)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	count := func() int {
+		var n int
+		require.NoError(t, db.QueryRow(`SELECT count(*) FROM test`).Scan(&n))
+		return n
+	}
+
+	// Flush every ten rows.
+	require.NoError(t, a.SetFlushPolicy(FlushPolicy{Rows: 10}))
+	for i := 0; i < 25; i++ {
+		require.NoError(t, a.AppendRow(int32(i), "a"))
+	}
+	require.Equal(t, 20, count())
+
+	stats := a.Stats()
+	require.Equal(t, int64(25), stats.RowsAppended)
+	require.Equal(t, int64(2), stats.ChunksFlushed)
+	require.Equal(t, int64(2), stats.Flushes)
+	require.Positive(t, stats.LastFlushLatency)
+	require.GreaterOrEqual(t, stats.TotalFlushLatency, stats.LastFlushLatency)
+	require.NoError(t, stats.LastError)
+
+	require.NoError(t, a.Flush())
+	require.Equal(t, 25, count())
+
+	// Flush, if the rows exceed the memory threshold.
+	require.NoError(t, a.SetFlushPolicy(FlushPolicy{MemoryBytes: 1024}))
+	require.NoError(t, a.AppendRow(int32(25), "short"))
+	require.Equal(t, 25, count())
+	require.NoError(t, a.AppendRow(int32(26), strings.Repeat("long", 256)))
+	require.Equal(t, 27, count())
+
+	// Flush in the background.
+	require.NoError(t, a.SetFlushPolicy(FlushPolicy{Interval: 10 * time.Millisecond, Background: true}))
+	require.NoError(t, a.AppendRow(int32(27), "b"))
+	require.Eventually(t, func() bool {
+		return a.Stats().RowsAppended == 28 && count() == 28
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err := a.SetFlushPolicy(FlushPolicy{Background: true})
+	testError(t, err, errAPI.Error(), invalidInputErrMsg)
+}
+
+func TestAppenderFlushPolicyError(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (id INTEGER PRIMARY KEY)`)
+	defer closeDriverConnWrapper(t, &conn)
+	defer closeConnectorWrapper(t, c)
+	defer closeDbWrapper(t, db)
+
+	require.NoError(t, a.SetFlushPolicy(FlushPolicy{Rows: 2}))
+	require.NoError(t, a.AppendRow(int32(1)))
+	err := a.AppendRow(int32(1))
+	testError(t, err, errAppenderFlush.Error())
+	require.Equal(t, err, a.Stats().LastError)
+	require.Error(t, a.Close())
+}
+
+func TestAppenderRejectRows(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (id INTEGER, s STRUCT(x INTEGER), i INTEGER)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	// A failed row is rolled back, and does not affect the next row.
+	require.NoError(t, a.AppendRow(int32(1), map[string]any{"x": int32(1)}, int32(1)))
+	err := a.AppendRow(nil, nil, "not an integer")
+	testError(t, err, errAppenderAppendRow.Error(), castErrMsg)
+	require.NoError(t, a.AppendRow(int32(2), map[string]any{"x": int32(2)}, int32(2)))
+
+	var rejected []RejectedRow
+	a.SetRejectHandler(func(r RejectedRow) {
+		rejected = append(rejected, r)
+	})
+	require.NoError(t, a.AppendRow(nil, nil, "not an integer"))
+	require.NoError(t, a.AppendRow(int32(3), map[string]any{"x": int32(3)}, int32(3)))
+	require.NoError(t, a.AppendMap(map[string]any{"id": "not an integer"}))
+	require.NoError(t, a.AppendMap(map[string]any{"id": int32(4), "s": map[string]any{"x": int32(4)}, "i": int32(4)}))
+
+	require.Len(t, rejected, 2)
+	require.Equal(t, int64(2), rejected[0].Row)
+	require.Equal(t, 2, rejected[0].Column)
+	require.Equal(t, []driver.Value{nil, nil, "not an integer"}, rejected[0].Values)
+	require.ErrorContains(t, rejected[0].Err, castErrMsg)
+	require.Equal(t, int64(4), rejected[1].Row)
+	require.Equal(t, 0, rejected[1].Column)
+
+	stats := a.Stats()
+	require.Equal(t, int64(4), stats.RowsAppended)
+	require.Equal(t, int64(2), stats.RowsRejected)
+
+	// Disable the reject mode.
+	a.SetRejectHandler(nil)
+	err = a.AppendRow("not an integer", nil, nil)
+	testError(t, err, errAppenderAppendRow.Error(), castErrMsg)
+	require.NoError(t, a.Flush())
+
+	res, err := db.Query(`SELECT id, s.x, i FROM test ORDER BY id`)
+	require.NoError(t, err)
+	defer closeRowsWrapper(t, res)
+
+	expected := int32(1)
+	for res.Next() {
+		var id, x, i *int32
+		require.NoError(t, res.Scan(&id, &x, &i))
+		require.NotNil(t, id)
+		require.NotNil(t, x)
+		require.NotNil(t, i)
+		require.Equal(t, expected, *id)
+		require.Equal(t, expected, *x)
+		require.Equal(t, expected, *i)
+		expected++
+	}
+	require.NoError(t, res.Err())
+	require.Equal(t, int32(5), expected)
+}
+
+func TestAppenderContext(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (id BIGINT)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	ctx := context.Background()
+	require.NoError(t, a.AppendRowContext(ctx, int64(1)))
+	require.NoError(t, a.FlushContext(ctx))
+
+	// A done context does not append, flush, or close.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.ErrorIs(t, a.AppendRowContext(cancelled, int64(2)), context.Canceled)
+	require.NoError(t, a.AppendRowContext(ctx, int64(3)))
+	require.ErrorIs(t, a.FlushContext(cancelled), context.Canceled)
+	require.ErrorIs(t, a.CloseContext(cancelled), context.Canceled)
+
+	var ids string
+	require.NoError(t, db.QueryRow(`SELECT string_agg(id::VARCHAR, ',' ORDER BY id) FROM test`).Scan(&ids))
+	require.Equal(t, "1", ids)
+
+	// The appender is still open.
+	require.NoError(t, a.AppendRowContext(ctx, int64(4)))
+	require.NoError(t, a.FlushContext(ctx))
+	require.NoError(t, db.QueryRow(`SELECT string_agg(id::VARCHAR, ',' ORDER BY id) FROM test`).Scan(&ids))
+	require.Equal(t, "1,3,4", ids)
+}
+
+func TestAppenderContextDeadline(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (id VARCHAR PRIMARY KEY)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	// Index the keys of enough rows for a flush of several milliseconds.
+	const rowCount = 200000
+	for i := range rowCount {
+		require.NoError(t, a.AppendRow(fmt.Sprintf("key%09d", i)))
+	}
+
+	// The deadline is exceeded while the appender flushes.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	err := a.FlushContext(ctx)
+	stats := a.Stats()
+	require.Equal(t, int64(1), stats.Flushes)
+	require.Greater(t, stats.LastFlushLatency, time.Millisecond)
+
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT count(*) FROM test`).Scan(&count))
+	if err == nil {
+		// DuckDB completed the flush before it noticed the interrupt.
+		require.Equal(t, rowCount, count)
+	} else {
+		// DuckDB stopped the flush, and the rows remain in the appender.
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Zero(t, count)
+	}
+
+	// The interrupt does not affect the next flush.
+	require.NoError(t, a.AppendRow("last"))
+	require.NoError(t, a.Flush())
+	require.NoError(t, db.QueryRow(`SELECT count(*) FROM test`).Scan(&count))
+	require.Equal(t, rowCount+1, count)
+}
@@ -0,0 +1,366 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// TypedAppender appends Go structs of type T to a table. It maps the struct fields to the table's columns
+// by their `duckdb` tag, or by their name, case-insensitively. Fields with the tag `duckdb:"-"` are skipped.
+// The columns without a field get their DEFAULT values, or NULL.
+// T must be a struct, or a pointer to a struct.
+type TypedAppender[T any] struct {
+	a *Appender
+	// The encoders of the appender's columns.
+	columns []typedColumn
+	// The row to append, reused across calls to Append.
+	row []driver.Value
+}
+
+// typedColumn maps a struct field to a column of the appender.
+type typedColumn struct {
+	// The index sequence of the field, see reflect.Value.FieldByIndex.
+	index  []int
+	encode encodeFn
+}
+
+// encodeFn converts a Go value into a value accepted by the setFn of a vector.
+type encodeFn func(v reflect.Value) any
+
+// NewTypedAppender returns a new TypedAppender for the default catalog from a DuckDB driver connection.
+// It validates the mapping of T to the table's columns, and returns an error, if a field has no column,
+// or if the type of a field cannot be appended to its column.
+func NewTypedAppender[T any](driverConn driver.Conn, schema, table string) (*TypedAppender[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, getError(errAppenderCreation, castError(reflect.TypeFor[T]().String(), reflect.Struct.String()))
+	}
+	fields := structFields(t)
+	if len(fields) == 0 {
+		return nil, getError(errAppenderCreation, structFieldError("no exported fields", "at least one field"))
+	}
+
+	a, err := NewAppenderFromConn(driverConn, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.initColumnIndexes(); err != nil {
+		return nil, getError(errAppenderCreation, closeAppenderOnError(a, err))
+	}
+
+	// Match the fields to the columns.
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		idx, ok := a.columnIndexes[strings.ToLower(f.name)]
+		if !ok {
+			err = fmt.Errorf("%w: %s (field %s)", errAppenderUnknownColumn, f.name, f.goName)
+			return nil, getError(errAppenderCreation, closeAppenderOnError(a, err))
+		}
+		names[i] = a.columnNames[idx]
+	}
+
+	// Only append to the matched columns, so that the other columns get their DEFAULT values.
+	if len(fields) != len(a.columnNames) {
+		if err = a.Close(); err != nil {
+			return nil, err
+		}
+		if a, err = NewAppenderWithColumns(driverConn, "", schema, table, names); err != nil {
+			return nil, err
+		}
+	} else {
+		// Order the fields by their column.
+		ordered := make([]structField, len(fields))
+		for _, f := range fields {
+			ordered[a.columnIndexes[strings.ToLower(f.name)]] = f
+		}
+		fields = ordered
+	}
+
+	ta := &TypedAppender[T]{
+		a:       a,
+		columns: make([]typedColumn, len(fields)),
+		row:     make([]driver.Value, len(fields)),
+	}
+	for i, f := range fields {
+		encode, err := encoderFor(f.fieldType, &a.chunk.columns[i], f.goName)
+		if err != nil {
+			return nil, getError(errAppenderCreation, closeAppenderOnError(a, addIndexToError(err, i)))
+		}
+		ta.columns[i] = typedColumn{index: f.index, encode: encode}
+	}
+	return ta, nil
+}
+
+// Append loads v as a row into the appender.
+func (ta *TypedAppender[T]) Append(v T) error {
+	ta.a.mu.Lock()
+	defer ta.a.mu.Unlock()
+	if ta.a.closed {
+		return getError(errAppenderAppendAfterClose, nil)
+	}
+
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return getError(errAppenderAppendRow, interfaceIsNilError(reflect.TypeFor[T]().String()))
+		}
+		rv = rv.Elem()
+	}
+
+	for i, col := range ta.columns {
+		field, ok := fieldByIndex(rv, col.index)
+		if !ok {
+			// The field is promoted from a nil embedded pointer.
+			ta.row[i] = nil
+			continue
+		}
+		ta.row[i] = col.encode(field)
+	}
+
+	if err := ta.a.appendRowSlice(ta.row); err != nil {
+		return getError(errAppenderAppendRow, err)
+	}
+	return ta.a.autoFlush(context.Background())
+}
+
+// AppendAll loads the values of vs as rows into the appender.
+// It stops at the first value that it cannot append.
+func (ta *TypedAppender[T]) AppendAll(vs []T) error {
+	for _, v := range vs {
+		if err := ta.Append(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush the appended rows to the underlying table, see Appender.Flush.
+func (ta *TypedAppender[T]) Flush() error {
+	return ta.a.Flush()
+}
+
+// Close the appender, see Appender.Close.
+func (ta *TypedAppender[T]) Close() error {
+	return ta.a.Close()
+}
+
+// Appender returns the underlying Appender.
+func (ta *TypedAppender[T]) Appender() *Appender {
+	return ta.a
+}
+
+// closeAppenderOnError closes a and joins its error to err.
+func closeAppenderOnError(a *Appender, err error) error {
+	if errClose := a.Close(); errClose != nil {
+		return fmt.Errorf("%w, %w", err, errClose)
+	}
+	return err
+}
+
+// fieldByIndex returns the nested field of v. It returns false, if it traverses a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v, true
+}
+
+// encoderFor returns an encodeFn converting values of type t into values of the vector's type.
+// It returns an error, if t cannot be converted.
+func encoderFor(t reflect.Type, vec *vector, path string) (encodeFn, error) {
+	// Interfaces and JSON values are passed on as they are.
+	if t.Kind() == reflect.Interface || vec.json {
+		return encodeNillable(encodeInterface), nil
+	}
+	if t == reflect.TypeFor[*big.Int]() && vec.Type == TYPE_HUGEINT {
+		return encodeNillable(encodeInterface), nil
+	}
+	if t.Kind() == reflect.Pointer {
+		elem, err := encoderFor(t.Elem(), vec, path)
+		if err != nil {
+			return nil, err
+		}
+		return encodeNillable(func(v reflect.Value) any {
+			return elem(v.Elem())
+		}), nil
+	}
+
+	var fn encodeFn
+	var err error
+	switch vec.Type {
+	case TYPE_BOOLEAN:
+		if t.Kind() == reflect.Bool {
+			fn = func(v reflect.Value) any { return v.Bool() }
+		}
+	case TYPE_TINYINT, TYPE_SMALLINT, TYPE_INTEGER, TYPE_BIGINT, TYPE_UTINYINT, TYPE_USMALLINT, TYPE_UINTEGER,
+		TYPE_UBIGINT, TYPE_FLOAT, TYPE_DOUBLE, TYPE_HUGEINT:
+		fn = encodeNumeric(t)
+	case TYPE_DECIMAL:
+		if t == reflect.TypeFor[Decimal]() {
+			fn = encodeInterface
+		}
+	case TYPE_VARCHAR, TYPE_BLOB:
+		if t.Kind() == reflect.String {
+			fn = func(v reflect.Value) any { return v.String() }
+		} else if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			fn = encodeNillable(func(v reflect.Value) any { return v.Bytes() })
+		}
+	case TYPE_ENUM:
+		if t.Kind() == reflect.String {
+			fn = func(v reflect.Value) any { return v.String() }
+		}
+	case TYPE_UUID:
+		if t.Kind() == reflect.Array && t.Len() == uuidLength && t.Elem().Kind() == reflect.Uint8 {
+			fn = func(v reflect.Value) any { return v.Convert(reflect.TypeFor[UUID]()).Interface() }
+		}
+	case TYPE_TIMESTAMP, TYPE_TIMESTAMP_S, TYPE_TIMESTAMP_MS, TYPE_TIMESTAMP_NS, TYPE_TIMESTAMP_TZ,
+		TYPE_DATE, TYPE_TIME, TYPE_TIME_TZ:
+		if t == reflect.TypeFor[time.Time]() {
+			fn = encodeInterface
+		}
+	case TYPE_INTERVAL:
+		if t == reflect.TypeFor[Interval]() {
+			fn = encodeInterface
+		} else if t == reflect.TypeFor[time.Duration]() {
+			fn = func(v reflect.Value) any { return NewIntervalFromDuration(time.Duration(v.Int())) }
+		}
+	case TYPE_LIST, TYPE_ARRAY:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			fn, err = encodeSlice(t, vec, path)
+		}
+	case TYPE_STRUCT:
+		if t == reflect.TypeFor[map[string]any]() {
+			fn = encodeNillable(encodeInterface)
+		} else if t.Kind() == reflect.Struct {
+			fn, err = encodeStruct(t, vec, path)
+		}
+	case TYPE_MAP:
+		if t == reflect.TypeFor[Map]() || t == reflect.TypeFor[OrderedMap]() {
+			fn = encodeNillable(encodeInterface)
+		} else if t.Kind() == reflect.Map {
+			fn, err = encodeMap(t, vec, path)
+		}
+	case TYPE_UNION:
+		if t == reflect.TypeFor[Union]() {
+			fn = encodeInterface
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if fn == nil {
+		return nil, decodeError(path, castError(t.String(), typeToStringMap[vec.Type]))
+	}
+	return fn, nil
+}
+
+func encodeInterface(v reflect.Value) any {
+	return v.Interface()
+}
+
+// encodeNillable returns nil for nil values, and otherwise calls fn.
+func encodeNillable(fn encodeFn) encodeFn {
+	return func(v reflect.Value) any {
+		if canNil(v) && v.IsNil() {
+			return nil
+		}
+		return fn(v)
+	}
+}
+
+func encodeNumeric(t reflect.Type) encodeFn {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value) any { return v.Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(v reflect.Value) any { return v.Uint() }
+	case reflect.Float32, reflect.Float64:
+		return func(v reflect.Value) any { return v.Float() }
+	}
+	return nil
+}
+
+func encodeSlice(t reflect.Type, vec *vector, path string) (encodeFn, error) {
+	elem, err := encoderFor(t.Elem(), &vec.childVectors[0], path+"[]")
+	if err != nil {
+		return nil, err
+	}
+	return encodeNillable(func(v reflect.Value) any {
+		s := make([]any, v.Len())
+		for i := range s {
+			s[i] = elem(v.Index(i))
+		}
+		return s
+	}), nil
+}
+
+func encodeStruct(t reflect.Type, vec *vector, path string) (encodeFn, error) {
+	fields := structFields(t)
+	columns := make([]typedColumn, len(vec.structEntries))
+	for i, entry := range vec.structEntries {
+		name := entry.Name()
+		idx := -1
+		for j, f := range fields {
+			if f.name == name || (idx == -1 && strings.EqualFold(f.name, name)) {
+				idx = j
+			}
+		}
+		if idx == -1 {
+			return nil, decodeError(path, structFieldError("no field", name))
+		}
+		encode, err := encoderFor(fields[idx].fieldType, &vec.childVectors[i], joinPath(path, name))
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = typedColumn{index: fields[idx].index, encode: encode}
+	}
+
+	return func(v reflect.Value) any {
+		m := make(map[string]any, len(columns))
+		for i, col := range columns {
+			var val any
+			if field, ok := fieldByIndex(v, col.index); ok {
+				val = col.encode(field)
+			}
+			m[vec.structEntries[i].Name()] = val
+		}
+		return m
+	}, nil
+}
+
+func encodeMap(t reflect.Type, vec *vector, path string) (encodeFn, error) {
+	// A MAP is a LIST of STRUCT values with a key and a value child.
+	entries := &vec.childVectors[0]
+	key, err := encoderFor(t.Key(), &entries.childVectors[0], path+"[key]")
+	if err != nil {
+		return nil, err
+	}
+	value, err := encoderFor(t.Elem(), &entries.childVectors[1], path+"[value]")
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeNillable(func(v reflect.Value) any {
+		m := make(OrderedMap, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m = append(m, MapEntry{Key: key(iter.Key()), Value: value(iter.Value())})
+		}
+		return m
+	}), nil
+}
@@ -0,0 +1,195 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type typedAppenderPoint struct {
+	X int32
+	Y int32
+}
+
+type typedAppenderBase struct {
+	ID int64 `duckdb:"id"`
+}
+
+type typedAppenderRow struct {
+	typedAppenderBase
+	Tags    []string
+	Name    *string
+	Point   typedAppenderPoint `duckdb:"pos"`
+	Counts  map[string]int
+	Elapsed time.Duration
+	Ignored string `duckdb:"-"`
+}
+
+type typedAppenderLevel int16
+
+type typedAppenderEvent struct {
+	Payload string
+	Level   typedAppenderLevel
+}
+
+func TestTypedAppender(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	// The columns are in a different order than the struct fields.
+	_, err := db.Exec(`CREATE TABLE test (
+		name VARCHAR,
+		pos STRUCT(x INTEGER, y INTEGER),
+		elapsed INTERVAL,
+		counts MAP(VARCHAR, BIGINT),
+		tags VARCHAR[],
+		id BIGINT
+	)`)
+	require.NoError(t, err)
+
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	name := "b"
+	rows := []typedAppenderRow{
+		{
+			typedAppenderBase: typedAppenderBase{ID: 1},
+			Tags:              []string{"x", "y"},
+			Point:             typedAppenderPoint{X: 1, Y: 2},
+			Counts:            map[string]int{"k": 3},
+			Elapsed:           time.Second,
+			Ignored:           "ignored",
+		},
+		{
+			typedAppenderBase: typedAppenderBase{ID: 2},
+			Name:              &name,
+			Point:             typedAppenderPoint{X: 3, Y: 4},
+		},
+	}
+
+	err = conn.Raw(func(driverConn any) error {
+		a, innerErr := NewTypedAppender[typedAppenderRow](driverConn.(driver.Conn), "", "test")
+		require.NoError(t, innerErr)
+		require.NoError(t, a.AppendAll(rows))
+		return a.Close()
+	})
+	require.NoError(t, err)
+
+	res, err := db.Query(`SELECT id, name, pos.x, pos.y, counts['k'], elapsed, tags::VARCHAR FROM test ORDER BY id`)
+	require.NoError(t, err)
+	defer closeRowsWrapper(t, res)
+
+	type result struct {
+		id      int64
+		name    *string
+		x, y    int32
+		count   *int64
+		elapsed *Interval
+		tags    *string
+	}
+	var results []result
+	for res.Next() {
+		var r result
+		require.NoError(t, res.Scan(&r.id, &r.name, &r.x, &r.y, &r.count, &r.elapsed, &r.tags))
+		results = append(results, r)
+	}
+	require.NoError(t, res.Err())
+	require.Len(t, results, 2)
+
+	require.Equal(t, int64(1), results[0].id)
+	require.Nil(t, results[0].name)
+	require.Equal(t, int32(1), results[0].x)
+	require.Equal(t, int32(2), results[0].y)
+	require.Equal(t, int64(3), *results[0].count)
+	require.Equal(t, NewIntervalFromDuration(time.Second), *results[0].elapsed)
+	require.Equal(t, "[x, y]", *results[0].tags)
+
+	require.Equal(t, int64(2), results[1].id)
+	require.Equal(t, "b", *results[1].name)
+	require.Equal(t, int32(3), results[1].x)
+	require.Nil(t, results[1].tags)
+}
+
+func TestTypedAppenderDefaults(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+	_, err := db.Exec(createDefaultsTableSQL)
+	require.NoError(t, err)
+
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	err = conn.Raw(func(driverConn any) error {
+		_, innerErr := NewTypedAppender[*typedAppenderEvent](driverConn.(driver.Conn), "", "test")
+		testError(t, innerErr, errAppenderCreation.Error(), errAppenderUnknownColumn.Error())
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The omitted columns get their DEFAULT values, and named types are converted.
+	_, err = db.Exec(`ALTER TABLE test ADD COLUMN level SMALLINT`)
+	require.NoError(t, err)
+
+	err = conn.Raw(func(driverConn any) error {
+		a, innerErr := NewTypedAppender[*typedAppenderEvent](driverConn.(driver.Conn), "", "test")
+		require.NoError(t, innerErr)
+		require.NoError(t, a.Append(&typedAppenderEvent{Payload: "a", Level: 1}))
+		require.NoError(t, a.Append(&typedAppenderEvent{Payload: "b"}))
+
+		innerErr = a.Append(nil)
+		testError(t, innerErr, errAppenderAppendRow.Error(), interfaceIsNilErrMsg)
+		return a.Close()
+	})
+	require.NoError(t, err)
+
+	var count, levels int
+	var kinds string
+	err = db.QueryRow(`SELECT count(DISTINCT id), sum(level), string_agg(DISTINCT kind) FROM test WHERE created IS NOT NULL`).Scan(&count, &levels, &kinds)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, 1, levels)
+	require.Equal(t, "event", kinds)
+}
+
+func TestTypedAppenderErrors(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+	_, err := db.Exec(`CREATE TABLE test (id BIGINT, pos STRUCT(x INTEGER, z INTEGER))`)
+	require.NoError(t, err)
+
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	err = conn.Raw(func(driverConn any) error {
+		dc := driverConn.(driver.Conn)
+
+		_, innerErr := NewTypedAppender[int](dc, "", "test")
+		testError(t, innerErr, errAppenderCreation.Error(), castErrMsg)
+
+		_, innerErr = NewTypedAppender[struct{ id int64 }](dc, "", "test")
+		testError(t, innerErr, errAppenderCreation.Error(), structFieldErrMsg)
+
+		// A field without a column.
+		_, innerErr = NewTypedAppender[struct{ ID, Missing int64 }](dc, "", "test")
+		testError(t, innerErr, errAppenderCreation.Error(), errAppenderUnknownColumn.Error())
+
+		// A field with a type that cannot be appended to its column.
+		_, innerErr = NewTypedAppender[struct{ ID string }](dc, "", "test")
+		testError(t, innerErr, errAppenderCreation.Error(), castErrMsg)
+
+		// A STRUCT entry without a field.
+		_, innerErr = NewTypedAppender[struct{ Pos typedAppenderPoint }](dc, "", "test")
+		testError(t, innerErr, errAppenderCreation.Error(), structFieldErrMsg)
+
+		a, innerErr := NewTypedAppender[struct{ ID int64 }](dc, "", "test")
+		require.NoError(t, innerErr)
+		require.NoError(t, a.Close())
+		innerErr = a.Append(struct{ ID int64 }{ID: 1})
+		testError(t, innerErr, errAppenderAppendAfterClose.Error())
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,334 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// UpsertConfig configures the conflict handling of an UpsertAppender.
+type UpsertConfig struct {
+	// ConflictColumns are the columns of the conflict target, e.g., the columns of the table's primary key.
+	ConflictColumns []string
+	// UpdateColumns are the columns to update, if a row conflicts with an existing row.
+	// If empty, all columns except the ConflictColumns are updated.
+	UpdateColumns []string
+	// DoNothing keeps the existing rows, and skips the conflicting rows.
+	DoNothing bool
+}
+
+// UpsertResult are the row counts of an UpsertAppender flush.
+type UpsertResult struct {
+	// Inserted is the number of inserted rows.
+	Inserted int64
+	// Updated is the number of updated rows.
+	Updated int64
+}
+
+// UpsertAppender bulk-merges rows into a table with a primary key or a unique constraint.
+// It appends the rows to a temporary staging table, and merges them into the table on Flush,
+// with an INSERT ... ON CONFLICT statement inside a transaction.
+// The staging table has the table's columns and DEFAULT values, except for its generated columns.
+// The table checks its constraints when merging the rows.
+// Like the Appender, it is not possible to use the connection while the UpsertAppender is open.
+type UpsertAppender struct {
+	conn *Conn
+	// The staging appender.
+	a *Appender
+	// The quoted name of the staging table.
+	staging string
+	// The INSERT ... ON CONFLICT statement.
+	upsertSQL string
+	// The query counting the existing rows that conflict with staged rows, if the rows are updated.
+	conflictSQL string
+}
+
+// upsertStagingID numbers the staging tables of UpsertAppenders.
+var upsertStagingID atomic.Uint64
+
+// NewUpsertAppender returns a new UpsertAppender from a DuckDB driver connection.
+// The column names in config match case-insensitively.
+func NewUpsertAppender(driverConn driver.Conn, catalog, schema, table string, config UpsertConfig) (*UpsertAppender, error) {
+	conn, ok := driverConn.(*Conn)
+	if !ok {
+		return nil, getError(errInvalidCon, nil)
+	}
+	if conn.closed {
+		return nil, getError(errClosedCon, nil)
+	}
+	if len(config.ConflictColumns) == 0 {
+		return nil, getError(errAppenderCreation, invalidInputError("no conflict columns", "at least one conflict column"))
+	}
+
+	target := quoteIdentifiers(catalog, schema, table)
+	columns, err := insertableColumns(conn, target)
+	if err != nil {
+		return nil, getError(errAppenderCreation, err)
+	}
+
+	// The staging table has the non-generated columns of the table, and their DEFAULT values.
+	definitions := make([]string, len(columns))
+	for i, c := range columns {
+		definitions[i] = quoteIdentifiers(c.name) + " " + c.dataType
+		if c.defaultExpr != "" {
+			definitions[i] += " DEFAULT " + c.defaultExpr
+		}
+	}
+	stagingTable := fmt.Sprintf("duckdb_upsert_%d", upsertStagingID.Add(1))
+	staging := quoteIdentifiers("temp", "main", stagingTable)
+	query := fmt.Sprintf(`CREATE TEMPORARY TABLE %s (%s)`, quoteIdentifiers(stagingTable), strings.Join(definitions, ", "))
+	if _, err = conn.ExecContext(context.Background(), query, nil); err != nil {
+		return nil, getError(errAppenderCreation, err)
+	}
+
+	ua := &UpsertAppender{conn: conn, staging: staging}
+	a, err := NewAppender(driverConn, "temp", "main", stagingTable)
+	if err == nil {
+		ua.a = a
+		err = a.initColumnIndexes()
+	}
+	if err == nil {
+		err = ua.initStatements(target, config)
+	}
+	if err != nil {
+		if ua.a != nil {
+			err = closeAppenderOnError(ua.a, err)
+		}
+		return nil, getError(errAppenderCreation, errors.Join(err, ua.dropStaging()))
+	}
+	return ua, nil
+}
+
+// initStatements initializes the statements of a flush.
+func (ua *UpsertAppender) initStatements(target string, config UpsertConfig) error {
+	columnName := func(name string) (string, error) {
+		idx, ok := ua.a.columnIndexes[strings.ToLower(name)]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", errAppenderUnknownColumn, name)
+		}
+		return quoteIdentifiers(ua.a.columnNames[idx]), nil
+	}
+
+	keys := make([]string, len(config.ConflictColumns))
+	isKey := make(map[string]bool, len(keys))
+	var matches []string
+	for i, name := range config.ConflictColumns {
+		key, err := columnName(name)
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+		isKey[key] = true
+		matches = append(matches, fmt.Sprintf("t.%s = s.%s", key, key))
+	}
+
+	var updates []string
+	if !config.DoNothing {
+		for _, name := range config.UpdateColumns {
+			column, err := columnName(name)
+			if err != nil {
+				return err
+			}
+			updates = append(updates, column)
+		}
+		if len(config.UpdateColumns) == 0 {
+			for _, name := range ua.a.columnNames {
+				if column := quoteIdentifiers(name); !isKey[column] {
+					updates = append(updates, column)
+				}
+			}
+		}
+	}
+
+	action := "DO NOTHING"
+	if len(updates) != 0 {
+		sets := make([]string, len(updates))
+		for i, column := range updates {
+			sets[i] = fmt.Sprintf("%s = excluded.%s", column, column)
+		}
+		action = "DO UPDATE SET " + strings.Join(sets, ", ")
+		// Rows with the same key update the same row.
+		ua.conflictSQL = fmt.Sprintf(`SELECT count(DISTINCT (%s)) FROM %s s WHERE EXISTS (SELECT 1 FROM %s t WHERE %s)`,
+			"s."+strings.Join(keys, ", s."), ua.staging, target, strings.Join(matches, " AND "))
+	}
+	columns := make([]string, len(ua.a.columnNames))
+	for i, name := range ua.a.columnNames {
+		columns[i] = quoteIdentifiers(name)
+	}
+	list := strings.Join(columns, ", ")
+	ua.upsertSQL = fmt.Sprintf(`INSERT INTO %s (%s) SELECT %s FROM %s ON CONFLICT (%s) %s`,
+		target, list, list, ua.staging, strings.Join(keys, ", "), action)
+	return nil
+}
+
+// upsertColumn is a column of the table of an UpsertAppender.
+type upsertColumn struct {
+	name     string
+	dataType string
+	// The DEFAULT expression of the column, or empty.
+	defaultExpr string
+}
+
+// generatedColumnErrMsg is the message of the binder error of inserting into a generated column.
+const generatedColumnErrMsg = "Cannot insert into a generated column"
+
+// insertableColumns returns the columns of the table that accept inserted values, i.e., its non-generated columns.
+func insertableColumns(conn *Conn, target string) ([]upsertColumn, error) {
+	ctx := context.Background()
+	r, err := conn.QueryContext(ctx, `SELECT column_name, column_type, coalesce("default", '') FROM (DESCRIBE `+target+`)`, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []upsertColumn
+	dest := make([]driver.Value, 3)
+	for {
+		if err = r.Next(dest); err != nil {
+			break
+		}
+		columns = append(columns, upsertColumn{name: dest[0].(string), dataType: dest[1].(string), defaultExpr: dest[2].(string)})
+	}
+	if !errors.Is(err, io.EOF) {
+		return nil, errors.Join(err, r.Close())
+	}
+	if err = r.Close(); err != nil {
+		return nil, err
+	}
+
+	// Generated columns have no DEFAULT expression, and an INSERT statement cannot name them.
+	// The catalog does not mark generated columns, so detect them by the error of binding such an INSERT.
+	insertable := columns[:0]
+	for _, c := range columns {
+		if c.defaultExpr == "" {
+			query := fmt.Sprintf(`INSERT INTO %s (%s) SELECT NULL LIMIT 0`, target, quoteIdentifiers(c.name))
+			stmt, err := conn.PrepareContext(ctx, query)
+			var duckdbErr *Error
+			if errors.As(err, &duckdbErr) && duckdbErr.Type == ErrorTypeBinder &&
+				strings.Contains(duckdbErr.Msg, generatedColumnErrMsg) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err = stmt.Close(); err != nil {
+				return nil, err
+			}
+		}
+		insertable = append(insertable, c)
+	}
+	return insertable, nil
+}
+
+// AppendRow loads a row of values into the staging table, see Appender.AppendRow.
+func (ua *UpsertAppender) AppendRow(args ...driver.Value) error {
+	return ua.a.AppendRow(args...)
+}
+
+// Appender returns the Appender of the staging table, e.g., to append rows with AppendMap.
+// Do not close it, close the UpsertAppender instead.
+func (ua *UpsertAppender) Appender() *Appender {
+	return ua.a
+}
+
+// Flush merges the appended rows into the table, and returns the number of inserted and updated rows.
+// If rows have the same key, the last row wins, and they count as one inserted or updated row.
+// If Flush fails, none of the appended rows are merged, and they remain staged for the next Flush.
+func (ua *UpsertAppender) Flush() (UpsertResult, error) {
+	if err := ua.a.Flush(); err != nil {
+		return UpsertResult{}, err
+	}
+	res, err := ua.merge(context.Background())
+	if err != nil {
+		return UpsertResult{}, getError(errAppenderFlush, err)
+	}
+	return res, nil
+}
+
+// Close merges the remaining rows into the table, see Flush, and closes the UpsertAppender.
+func (ua *UpsertAppender) Close() (UpsertResult, error) {
+	if err := ua.a.Close(); err != nil {
+		return UpsertResult{}, errors.Join(err, ua.dropStaging())
+	}
+	res, err := ua.merge(context.Background())
+	if err != nil {
+		err = getError(errAppenderFlush, err)
+	}
+	return res, errors.Join(err, ua.dropStaging())
+}
+
+func (ua *UpsertAppender) merge(ctx context.Context) (res UpsertResult, err error) {
+	// Use the transaction of the connection, if there is one.
+	if !ua.conn.tx {
+		if _, err = ua.conn.ExecContext(ctx, `BEGIN TRANSACTION`, nil); err != nil {
+			return res, err
+		}
+		defer func() {
+			query := `COMMIT TRANSACTION`
+			if err != nil {
+				query = `ROLLBACK`
+			}
+			if _, errEnd := ua.conn.ExecContext(ctx, query, nil); errEnd != nil && err == nil {
+				res, err = UpsertResult{}, errEnd
+			}
+		}()
+	}
+
+	if ua.conflictSQL != "" {
+		if res.Updated, err = ua.queryCount(ctx, ua.conflictSQL); err != nil {
+			return res, err
+		}
+	}
+
+	result, err := ua.conn.ExecContext(ctx, ua.upsertSQL, nil)
+	if err != nil {
+		return UpsertResult{}, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return UpsertResult{}, err
+	}
+	res.Inserted = affected - res.Updated
+
+	if _, err = ua.conn.ExecContext(ctx, `DELETE FROM `+ua.staging, nil); err != nil {
+		return UpsertResult{}, err
+	}
+	return res, nil
+}
+
+func (ua *UpsertAppender) queryCount(ctx context.Context, query string) (int64, error) {
+	r, err := ua.conn.QueryContext(ctx, query, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	dest := make([]driver.Value, 1)
+	if err = r.Next(dest); err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	count, _ := dest[0].(int64)
+	return count, nil
+}
+
+func (ua *UpsertAppender) dropStaging() error {
+	_, err := ua.conn.ExecContext(context.Background(), `DROP TABLE IF EXISTS `+ua.staging, nil)
+	return err
+}
+
+// quoteIdentifiers returns the qualified name of the non-empty identifiers, e.g., "schema"."table".
+func quoteIdentifiers(identifiers ...string) string {
+	var quoted []string
+	for _, identifier := range identifiers {
+		if identifier != "" {
+			quoted = append(quoted, `"`+strings.ReplaceAll(identifier, `"`, `""`)+`"`)
+		}
+	}
+	return strings.Join(quoted, ".")
+}
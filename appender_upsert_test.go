@@ -0,0 +1,142 @@
+package duckdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertAppender(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (id INTEGER PRIMARY KEY, name VARCHAR, version INTEGER)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	_, err := db.Exec(`INSERT INTO test VALUES (1, 'a', 1), (2, 'b', 1)`)
+	require.NoError(t, err)
+
+	ua, err := NewUpsertAppender(conn, "", "", "test", UpsertConfig{ConflictColumns: []string{"ID"}})
+	require.NoError(t, err)
+	require.NoError(t, ua.AppendRow(int32(2), "B", int32(2)))
+	require.NoError(t, ua.AppendRow(int32(3), "c", int32(1)))
+	require.NoError(t, ua.Appender().AppendMap(map[string]any{"id": int32(4), "name": "d", "version": int32(1)}))
+
+	res, err := ua.Flush()
+	require.NoError(t, err)
+	require.Equal(t, UpsertResult{Inserted: 2, Updated: 1}, res)
+
+	// An empty flush.
+	res, err = ua.Flush()
+	require.NoError(t, err)
+	require.Equal(t, UpsertResult{}, res)
+
+	// The last row with the same key wins.
+	require.NoError(t, ua.AppendRow(int32(1), "x", int32(2)))
+	require.NoError(t, ua.AppendRow(int32(1), "A", int32(2)))
+	res, err = ua.Close()
+	require.NoError(t, err)
+	require.Equal(t, UpsertResult{Updated: 1}, res)
+
+	// Only update the version.
+	ua, err = NewUpsertAppender(conn, "", "", "test", UpsertConfig{
+		ConflictColumns: []string{"id"},
+		UpdateColumns:   []string{"version"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ua.AppendRow(int32(3), "ignored", int32(3)))
+	res, err = ua.Close()
+	require.NoError(t, err)
+	require.Equal(t, UpsertResult{Updated: 1}, res)
+
+	// Skip the conflicting rows.
+	ua, err = NewUpsertAppender(conn, "", "", "test", UpsertConfig{ConflictColumns: []string{"id"}, DoNothing: true})
+	require.NoError(t, err)
+	require.NoError(t, ua.AppendRow(int32(4), "ignored", int32(4)))
+	require.NoError(t, ua.AppendRow(int32(5), "e", int32(1)))
+	res, err = ua.Close()
+	require.NoError(t, err)
+	require.Equal(t, UpsertResult{Inserted: 1}, res)
+
+	rows, err := db.Query(`SELECT id, name, version FROM test ORDER BY id`)
+	require.NoError(t, err)
+	defer closeRowsWrapper(t, rows)
+
+	type row struct {
+		id      int32
+		name    string
+		version int32
+	}
+	var actual []row
+	for rows.Next() {
+		var r row
+		require.NoError(t, rows.Scan(&r.id, &r.name, &r.version))
+		actual = append(actual, r)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, []row{{1, "A", 2}, {2, "B", 2}, {3, "c", 3}, {4, "d", 1}, {5, "e", 1}}, actual)
+
+	// The staging tables are dropped.
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT count(*) FROM duckdb_tables() WHERE temporary`).Scan(&count))
+	require.Zero(t, count)
+}
+
+func TestUpsertAppenderGeneratedColumns(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (
+		id INTEGER PRIMARY KEY,
+		doubled INTEGER GENERATED ALWAYS AS (id * 2),
+		name VARCHAR DEFAULT 'anon' NOT NULL,
+		version INTEGER DEFAULT 1
+	)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	_, err := db.Exec(`INSERT INTO test (id, name) VALUES (1, 'a')`)
+	require.NoError(t, err)
+
+	ua, err := NewUpsertAppender(conn, "", "", "test", UpsertConfig{ConflictColumns: []string{"id"}, UpdateColumns: []string{"name"}})
+	require.NoError(t, err)
+	require.NoError(t, ua.AppendRow(int32(1), "A", int32(3)))
+	require.NoError(t, ua.Appender().AppendMap(map[string]any{"id": int32(2)}))
+	res, err := ua.Close()
+	require.NoError(t, err)
+	require.Equal(t, UpsertResult{Inserted: 1, Updated: 1}, res)
+
+	var actual string
+	err = db.QueryRow(`SELECT string_agg(concat_ws(':', id, doubled, name, version), ',' ORDER BY id) FROM test`).Scan(&actual)
+	require.NoError(t, err)
+	require.Equal(t, "1:2:A:1,2:4:anon:1", actual)
+}
+
+func TestUpsertAppenderErrors(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (id INTEGER PRIMARY KEY, name VARCHAR NOT NULL)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	_, err := NewUpsertAppender(conn, "", "", "test", UpsertConfig{})
+	testError(t, err, errAppenderCreation.Error(), invalidInputErrMsg)
+
+	_, err = NewUpsertAppender(conn, "", "", "test", UpsertConfig{ConflictColumns: []string{"unknown"}})
+	testError(t, err, errAppenderCreation.Error(), errAppenderUnknownColumn.Error())
+
+	_, err = NewUpsertAppender(conn, "", "", "does_not_exist", UpsertConfig{ConflictColumns: []string{"id"}})
+	testError(t, err, errAppenderCreation.Error())
+
+	// Errors other than of generated columns are not hidden.
+	_, err = db.Exec(`CREATE VIEW test_view AS SELECT * FROM test`)
+	require.NoError(t, err)
+	_, err = NewUpsertAppender(conn, "", "", "test_view", UpsertConfig{ConflictColumns: []string{"id"}})
+	testError(t, err, errAppenderCreation.Error(), "Catalog Error")
+
+	// A NOT NULL violation.
+	ua, err := NewUpsertAppender(conn, "", "", "test", UpsertConfig{ConflictColumns: []string{"id"}})
+	require.NoError(t, err)
+	require.NoError(t, ua.AppendRow(int32(1), "a"))
+	require.NoError(t, ua.AppendRow(int32(2), nil))
+	_, err = ua.Flush()
+	testError(t, err, errAppenderFlush.Error())
+	_, err = ua.Close()
+	testError(t, err, errAppenderFlush.Error())
+
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT count(*) FROM test`).Scan(&count))
+	require.Zero(t, count)
+	require.NoError(t, db.QueryRow(`SELECT count(*) FROM duckdb_tables() WHERE temporary`).Scan(&count))
+	require.Zero(t, count)
+}
@@ -63,6 +63,9 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"unsafe"
 
 	"github.com/marcboeker/go-duckdb/arrowmapping"
@@ -76,7 +79,12 @@ import (
 // Arrow exposes DuckDB Apache Arrow interface.
 // https://duckdb.org/docs/api/c/api#arrow-interface
 type Arrow struct {
-	conn *Conn
+	conn    *Conn
+	options *ArrowOptions
+
+	mu sync.Mutex
+	// The tables registered with RegisterTable and RegisterRecords.
+	tables map[string]*arrowTable
 }
 
 // NewArrowFromConn returns a new Arrow from a DuckDB driver connection.
@@ -92,8 +100,69 @@ func NewArrowFromConn(driverConn driver.Conn) (*Arrow, error) {
 	return &Arrow{conn: conn}, nil
 }
 
+// ArrowOptions configures how DuckDB exports the query results of an Arrow.
+// Unlike the connection settings, e.g., SET arrow_large_buffer_size = true, they only apply to the Arrow's queries.
+// A nil option keeps the connection setting.
+type ArrowOptions struct {
+	// LargeBufferSize exports strings, blobs, and lists with 64-bit offsets, e.g., arrow.LARGE_STRING
+	// instead of arrow.STRING. See the DuckDB setting arrow_large_buffer_size.
+	LargeBufferSize *bool
+	// LosslessConversion exports types without an Arrow equivalent, e.g., UUID, as Arrow extension types,
+	// instead of converting them. See the DuckDB setting arrow_lossless_conversion.
+	LosslessConversion *bool
+	// StringView exports strings and blobs as arrow.STRING_VIEW and arrow.BINARY_VIEW.
+	// See the DuckDB setting produce_arrow_string_view.
+	StringView *bool
+	// ListView exports lists as arrow.LIST_VIEW. See the DuckDB setting arrow_output_list_view.
+	ListView *bool
+	// Schema is the expected schema of the query results. If set, QueryContext fails before returning
+	// a record, if the result's fields do not have the same names and types.
+	Schema *arrow.Schema
+}
+
+// arrowSetting is a DuckDB setting of the Arrow export.
+type arrowSetting struct {
+	name  string
+	value bool
+}
+
+// arrowSettings returns the DuckDB settings of the non-nil options.
+func (opts *ArrowOptions) arrowSettings() []arrowSetting {
+	var settings []arrowSetting
+	for _, option := range []struct {
+		name  string
+		value *bool
+	}{
+		{name: "arrow_large_buffer_size", value: opts.LargeBufferSize},
+		{name: "arrow_lossless_conversion", value: opts.LosslessConversion},
+		{name: "produce_arrow_string_view", value: opts.StringView},
+		{name: "arrow_output_list_view", value: opts.ListView},
+	} {
+		if option.value != nil {
+			settings = append(settings, arrowSetting{name: option.name, value: *option.value})
+		}
+	}
+	return settings
+}
+
+// NewArrowFromConnWithOptions returns a new Arrow from a DuckDB driver connection, see NewArrowFromConn.
+// Its queries apply the options, and restore the previous settings of the connection afterwards.
+func NewArrowFromConnWithOptions(driverConn driver.Conn, options ArrowOptions) (*Arrow, error) {
+	a, err := NewArrowFromConn(driverConn)
+	if err != nil {
+		return nil, err
+	}
+	a.options = &options
+	return a, nil
+}
+
 // QueryContext prepares statements, executes them, returns Apache Arrow array.RecordReader as a result of the last
 // executed statement. Arguments are bound to the last statement.
+// DuckDB materializes the whole result when it executes the query, and the reader holds it until it is released.
+// Only the conversion of the result to Arrow records is lazy: the reader converts a record on each Next.
+// If ctx is done during the execution, it interrupts the query. Afterwards, the reader stops with ctx.Err(),
+// if ctx is done between two records.
+// It must be released to release the DuckDB result.
 func (a *Arrow) QueryContext(ctx context.Context, query string, args ...any) (array.RecordReader, error) {
 	if a.conn.closed {
 		return nil, errClosedCon
@@ -125,45 +194,215 @@ func (a *Arrow) QueryContext(ctx context.Context, query string, args ...any) (ar
 	if err != nil {
 		return nil, err
 	}
-	defer stmt.Close()
 
-	res, err := a.execute(stmt, a.anyArgsToNamedArgs(args))
+	res, sc, err := a.executeWithOptions(ctx, stmt, a.anyArgsToNamedArgs(args))
 	if err != nil {
-		return nil, err
+		return nil, errors.Join(err, stmt.Close())
+	}
+
+	r := &arrowRecordReader{
+		ctx:      ctx,
+		a:        a,
+		stmt:     stmt,
+		res:      res,
+		schema:   sc,
+		rowCount: uint64(arrowmapping.ArrowRowCount(*res)),
+	}
+	r.refCount.Store(1)
+	return r, nil
+}
+
+// arrowRecordReader is an array.RecordReader, which lazily converts the records of a materialized DuckDB Arrow result.
+type arrowRecordReader struct {
+	refCount atomic.Int64
+
+	ctx  context.Context
+	a    *Arrow
+	stmt *Stmt
+	// The Arrow result. It is nil after all records have been fetched.
+	res    *arrowmapping.Arrow
+	schema *arrow.Schema
+
+	rowCount      uint64
+	retrievedRows uint64
+	// The current record.
+	rec arrow.Record
+	err error
+}
+
+// Retain increases the reference count of the reader.
+func (r *arrowRecordReader) Retain() {
+	r.refCount.Add(1)
+}
+
+// Release decreases the reference count of the reader. If it reaches zero,
+// it releases the current record and the DuckDB result.
+func (r *arrowRecordReader) Release() {
+	if r.refCount.Add(-1) != 0 {
+		return
 	}
-	defer arrowmapping.DestroyArrow(res)
+	if r.rec != nil {
+		r.rec.Release()
+		r.rec = nil
+	}
+	r.close()
+}
 
-	sc, err := a.queryArrowSchema(res)
+// Schema returns the schema of the records.
+func (r *arrowRecordReader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+// Next fetches the next record. It returns false, if there are no more records, or if an error occurred, see Err.
+// It releases the previous record.
+func (r *arrowRecordReader) Next() bool {
+	if r.rec != nil {
+		r.rec.Release()
+		r.rec = nil
+	}
+	if r.res == nil {
+		return false
+	}
+	if r.retrievedRows >= r.rowCount {
+		r.close()
+		return false
+	}
+	if err := r.ctx.Err(); err != nil {
+		r.err = err
+		r.close()
+		return false
+	}
+
+	rec, err := r.a.queryArrowArray(r.res, r.schema)
 	if err != nil {
-		return nil, err
+		r.err = err
+		r.close()
+		return false
+	}
+	if rec.NumRows() == 0 {
+		rec.Release()
+		r.close()
+		return false
 	}
+	r.rec = rec
+	r.retrievedRows += uint64(rec.NumRows())
+	return true
+}
 
-	var recs []arrow.Record
-	defer func() {
-		for _, r := range recs {
-			r.Release()
-		}
-	}()
+// Record returns the current record. It is only valid until the next call to Next.
+func (r *arrowRecordReader) Record() arrow.Record {
+	return r.rec
+}
 
-	rowCount := uint64(arrowmapping.ArrowRowCount(*res))
-	var retrievedRows uint64
-	for retrievedRows < rowCount {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
+// Err returns the error that stopped Next, if any.
+func (r *arrowRecordReader) Err() error {
+	return r.err
+}
+
+// close releases the DuckDB result and the statement.
+func (r *arrowRecordReader) close() {
+	if r.res == nil {
+		return
+	}
+	arrowmapping.DestroyArrow(r.res)
+	r.res = nil
+	if err := r.stmt.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+// executeWithOptions executes the statement with the settings of the options, and fetches the schema of the result.
+func (a *Arrow) executeWithOptions(ctx context.Context, s *Stmt, args []driver.NamedValue) (res *arrowmapping.Arrow, sc *arrow.Schema, err error) {
+	if a.options != nil {
+		var restore func() error
+		if restore, err = a.applySettings(ctx, a.options.arrowSettings()); err != nil {
+			return nil, nil, err
 		}
+		defer func() {
+			if errRestore := restore(); errRestore != nil && err == nil {
+				arrowmapping.DestroyArrow(res)
+				res, sc, err = nil, nil, errRestore
+			}
+		}()
+	}
 
-		rec, err := a.queryArrowArray(res, sc)
-		if err != nil {
-			return nil, err
+	if res, err = a.execute(ctx, s, args); err != nil {
+		return nil, nil, err
+	}
+	sc, err = a.queryArrowSchema(res)
+	if err == nil && a.options != nil && a.options.Schema != nil {
+		err = checkArrowSchema(sc, a.options.Schema)
+	}
+	if err != nil {
+		arrowmapping.DestroyArrow(res)
+		return nil, nil, err
+	}
+	return res, sc, nil
+}
+
+// applySettings sets the settings that differ from the current settings,
+// and returns a function restoring the previous settings.
+func (a *Arrow) applySettings(ctx context.Context, settings []arrowSetting) (func() error, error) {
+	if len(settings) == 0 {
+		return func() error { return nil }, nil
+	}
+	current := make([]driver.Value, len(settings))
+	columns := make([]string, len(settings))
+	for i, setting := range settings {
+		columns[i] = fmt.Sprintf(`current_setting('%s')`, setting.name)
+	}
+	r, err := a.conn.QueryContext(ctx, `SELECT `+strings.Join(columns, ", "), nil)
+	if err != nil {
+		return nil, err
+	}
+	err = r.Next(current)
+	if errClose := r.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var set, reset []string
+	for i, setting := range settings {
+		if value, _ := current[i].(bool); value != setting.value {
+			set = append(set, fmt.Sprintf(`SET %s = %t`, setting.name, setting.value))
+			reset = append(reset, fmt.Sprintf(`SET %s = %t`, setting.name, value))
 		}
+	}
 
-		recs = append(recs, rec)
-		retrievedRows += uint64(rec.NumRows())
+	restore := func() error {
+		if len(reset) == 0 {
+			return nil
+		}
+		_, err := a.conn.ExecContext(context.Background(), strings.Join(reset, "; "), nil)
+		return err
+	}
+	if len(set) != 0 {
+		if _, err = a.conn.ExecContext(ctx, strings.Join(set, "; "), nil); err != nil {
+			return nil, errors.Join(err, restore())
+		}
 	}
+	return restore, nil
+}
 
-	return array.NewRecordReader(sc, recs)
+// checkArrowSchema returns an error, if the fields of the schema do not have the names and types of the expected fields.
+// It ignores the nullability and the metadata of the fields.
+func checkArrowSchema(sc *arrow.Schema, expected *arrow.Schema) error {
+	if sc.NumFields() != expected.NumFields() {
+		return getError(errArrowSchemaMismatch, columnCountError(sc.NumFields(), expected.NumFields()))
+	}
+	for i, field := range sc.Fields() {
+		expectedField := expected.Field(i)
+		if field.Name != expectedField.Name {
+			return getError(errArrowSchemaMismatch, addIndexToError(structFieldError(field.Name, expectedField.Name), i))
+		}
+		if !arrow.TypeEqual(field.Type, expectedField.Type) {
+			err := castError(field.Type.String(), expectedField.Type.String())
+			return getError(errArrowSchemaMismatch, addIndexToError(err, i))
+		}
+	}
+	return nil
 }
 
 // queryArrowSchema fetches the internal arrow schema from the arrow result.
@@ -215,7 +454,7 @@ func (a *Arrow) queryArrowArray(res *arrowmapping.Arrow, sc *arrow.Schema) (arro
 	return rec, nil
 }
 
-func (a *Arrow) execute(s *Stmt, args []driver.NamedValue) (*arrowmapping.Arrow, error) {
+func (a *Arrow) execute(ctx context.Context, s *Stmt, args []driver.NamedValue) (*arrowmapping.Arrow, error) {
 	if s.closed {
 		return nil, errClosedCon
 	}
@@ -223,10 +462,28 @@ func (a *Arrow) execute(s *Stmt, args []driver.NamedValue) (*arrowmapping.Arrow,
 		return nil, err
 	}
 
+	// Interrupt the query, if ctx is done during the execution, see Stmt.executeBound.
+	mainDoneCh := make(chan struct{})
+	bgDoneCh := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			mapping.Interrupt(a.conn.conn)
+		case <-mainDoneCh:
+		}
+		close(bgDoneCh)
+	}()
+
 	var res arrowmapping.Arrow
-	if arrowmapping.ExecutePreparedArrow(*s.preparedStmt, &res) == mapping.StateError {
+	state := arrowmapping.ExecutePreparedArrow(*s.preparedStmt, &res)
+	close(mainDoneCh)
+	<-bgDoneCh
+	if state == mapping.StateError {
 		errMsg := arrowmapping.QueryArrowError(res)
 		arrowmapping.DestroyArrow(&res)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		return nil, fmt.Errorf("failed to execute the prepared arrow: %v", errMsg)
 	}
 
@@ -247,6 +504,8 @@ func (a *Arrow) anyArgsToNamedArgs(args []any) []driver.NamedValue {
 }
 
 // RegisterView registers an Arrow record reader as a view with the given name in DuckDB.
+// The view consumes the reader, so only the first query of the view returns rows. See RegisterTable for a view,
+// which can be scanned repeatedly.
 // The returned release function must be called to release the memory once the view is no longer needed.
 func (a *Arrow) RegisterView(reader array.RecordReader, name string) (release func(), err error) {
 	if a.conn.closed {
@@ -0,0 +1,53 @@
+//go:build duckdb_arrow
+
+package duckdb
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+)
+
+// WriteIPC executes the query, see QueryContext, and writes its result to w in the Apache Arrow IPC stream format.
+// DuckDB materializes the whole result, but WriteIPC converts and writes one record at a time.
+// It does not close w.
+func (a *Arrow) WriteIPC(ctx context.Context, w io.Writer, query string, args ...any) error {
+	r, err := a.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer r.Release()
+
+	writer := ipc.NewWriter(w, ipc.WithSchema(r.Schema()))
+	for r.Next() {
+		if err = writer.Write(r.Record()); err != nil {
+			return errors.Join(err, writer.Close())
+		}
+	}
+	if err = r.Err(); err != nil {
+		return errors.Join(err, writer.Close())
+	}
+	return writer.Close()
+}
+
+// RegisterIPC reads an Apache Arrow IPC stream from r, and registers its records as a view with the given name
+// in DuckDB, see RegisterTable. It reads the whole stream before it returns. Call Unregister to release the records.
+func (a *Arrow) RegisterIPC(r io.Reader, name string) error {
+	reader, err := ipc.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer reader.Release()
+
+	t := &arrowTable{schema: reader.Schema()}
+	for reader.Next() {
+		t.add(reader.Record())
+	}
+	if err = reader.Err(); err != nil {
+		t.release()
+		return err
+	}
+	return a.registerTable(t, name)
+}
@@ -0,0 +1,309 @@
+//go:build duckdb_arrow
+
+package duckdb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// arrowTable holds the records of a table registered with Arrow.RegisterTable.
+type arrowTable struct {
+	// mu protects the records against a concurrent release.
+	mu     sync.RWMutex
+	schema *arrow.Schema
+	// The records are sliced to at most GetDataChunkCapacity rows.
+	records  []arrow.Record
+	rows     int64
+	released bool
+}
+
+// arrowTableID numbers the table functions of registered Arrow tables.
+var arrowTableID atomic.Uint64
+
+// RegisterTable registers an Apache Arrow table as a temporary view with the given name in DuckDB.
+// The view is only visible on the connection of a.
+// Unlike a view of RegisterView, the view can be scanned repeatedly, and by multiple threads.
+// The table is retained until Unregister, so the caller may release it after RegisterTable returns.
+func (a *Arrow) RegisterTable(table arrow.Table, name string) error {
+	reader := array.NewTableReader(table, int64(GetDataChunkCapacity()))
+	defer reader.Release()
+
+	t := &arrowTable{schema: table.Schema()}
+	for reader.Next() {
+		rec := reader.Record()
+		rec.Retain()
+		t.records = append(t.records, rec)
+		t.rows += rec.NumRows()
+	}
+	return a.registerTable(t, name)
+}
+
+// RegisterRecords registers Apache Arrow records with the same schema as a temporary view with the given name in DuckDB,
+// see RegisterTable.
+func (a *Arrow) RegisterRecords(records []arrow.Record, name string) error {
+	if len(records) == 0 {
+		return getError(errArrowNoRecords, nil)
+	}
+
+	t := &arrowTable{schema: records[0].Schema()}
+	for i, rec := range records {
+		if !rec.Schema().Equal(t.schema) {
+			t.release()
+			err := invalidInputError(fmt.Sprintf("another schema for record %d", i), "the schema of the first record")
+			return getError(errArrowSchemaMismatch, err)
+		}
+		t.add(rec)
+	}
+	return a.registerTable(t, name)
+}
+
+func (a *Arrow) registerTable(t *arrowTable, name string) error {
+	if a.conn.closed {
+		t.release()
+		return errClosedCon
+	}
+
+	columns, err := arrowColumnInfos(t.schema)
+	if err != nil {
+		t.release()
+		return err
+	}
+
+	// The view selects from a table function, which scans the records again for each query.
+	// Like the table function, the view is temporary, so it does not persist in a database file.
+	function := fmt.Sprintf("duckdb_arrow_table_%d", arrowTableID.Add(1))
+	err = registerTableUDF(a.conn, function, ParallelChunkTableFunction{
+		BindArguments: func(map[string]any, ...any) (ParallelChunkTableSource, error) {
+			return &arrowTableSource{table: t, columns: columns}, nil
+		},
+	})
+	if err == nil {
+		query := fmt.Sprintf(`CREATE TEMP VIEW %s AS SELECT * FROM %s()`, quoteIdentifiers(name), function)
+		_, err = a.conn.ExecContext(context.Background(), query, nil)
+	}
+	if err != nil {
+		t.release()
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.tables == nil {
+		a.tables = make(map[string]*arrowTable)
+	}
+	a.tables[name] = t
+	return nil
+}
+
+// Unregister drops the temporary view with the given name, and releases the records of a table registered
+// with RegisterTable or RegisterRecords. Queries scanning the table after Unregister fail.
+// DuckDB cannot drop table functions, so the table function scanning the records, duckdb_arrow_table_<n>,
+// remains registered on the connection, without the records.
+func (a *Arrow) Unregister(name string) error {
+	if a.conn.closed {
+		return errClosedCon
+	}
+
+	_, err := a.conn.ExecContext(context.Background(), `DROP VIEW IF EXISTS `+quoteIdentifiers("temp", "main", name), nil)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	t := a.tables[name]
+	delete(a.tables, name)
+	a.mu.Unlock()
+
+	if t != nil {
+		t.release()
+	}
+	return nil
+}
+
+// add retains the record in slices of at most GetDataChunkCapacity rows.
+func (t *arrowTable) add(rec arrow.Record) {
+	capacity := int64(GetDataChunkCapacity())
+	for offset := int64(0); offset < rec.NumRows(); offset += capacity {
+		t.records = append(t.records, rec.NewSlice(offset, min(offset+capacity, rec.NumRows())))
+	}
+	t.rows += rec.NumRows()
+}
+
+func (t *arrowTable) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, rec := range t.records {
+		rec.Release()
+	}
+	t.records = nil
+	t.released = true
+}
+
+// arrowTableSource is a ParallelChunkTableSource, which scans the records of an arrowTable.
+type arrowTableSource struct {
+	table   *arrowTable
+	columns []ColumnInfo
+	// next is the index of the next record to scan.
+	next atomic.Int64
+}
+
+func (s *arrowTableSource) ColumnInfos() []ColumnInfo {
+	return s.columns
+}
+
+func (s *arrowTableSource) Cardinality() *CardinalityInfo {
+	return &CardinalityInfo{Cardinality: uint(s.table.rows), Exact: true}
+}
+
+func (s *arrowTableSource) Init() ParallelTableSourceInfo {
+	return ParallelTableSourceInfo{}
+}
+
+func (s *arrowTableSource) NewLocalState() any {
+	return &struct{}{}
+}
+
+func (s *arrowTableSource) FillChunk(_ any, chunk DataChunk) error {
+	s.table.mu.RLock()
+	defer s.table.mu.RUnlock()
+	if s.table.released {
+		return getError(errArrowTableUnregistered, nil)
+	}
+
+	i := int(s.next.Add(1) - 1)
+	if i >= len(s.table.records) {
+		return chunk.SetSize(0)
+	}
+
+	rec := s.table.records[i]
+	return fillArrowChunk(chunk, rec, 0, int(rec.NumRows()))
+}
+
+// arrowColumnInfos returns the column information of the fields of an Arrow schema.
+func arrowColumnInfos(schema *arrow.Schema) ([]ColumnInfo, error) {
+	columns := make([]ColumnInfo, schema.NumFields())
+	for i, field := range schema.Fields() {
+		info, err := arrowTypeInfo(field.Type)
+		if err != nil {
+			return nil, addIndexToError(err, i)
+		}
+		columns[i] = ColumnInfo{Name: field.Name, T: info}
+	}
+	return columns, nil
+}
+
+// fillArrowChunk copies n rows of the record, starting at offset, into the projected columns of the chunk.
+func fillArrowChunk(chunk DataChunk, rec arrow.Record, offset int, n int) error {
+	for colIdx := 0; colIdx < int(rec.NumCols()); colIdx++ {
+		vec, err := chunk.projectedColumn(colIdx)
+		if err != nil {
+			return err
+		}
+		if vec == nil {
+			continue
+		}
+		if err = setArrowColumn(vec, rec.Column(colIdx), offset, 0, n); err != nil {
+			return addIndexToError(err, colIdx)
+		}
+	}
+	return chunk.SetSize(n)
+}
+
+// arrowTypeInfo returns the TypeInfo of the DuckDB type of an Arrow type.
+func arrowTypeInfo(dt arrow.DataType) (TypeInfo, error) {
+	switch dt := dt.(type) {
+	case *arrow.BooleanType:
+		return NewTypeInfo(TYPE_BOOLEAN)
+	case *arrow.Int8Type:
+		return NewTypeInfo(TYPE_TINYINT)
+	case *arrow.Int16Type:
+		return NewTypeInfo(TYPE_SMALLINT)
+	case *arrow.Int32Type:
+		return NewTypeInfo(TYPE_INTEGER)
+	case *arrow.Int64Type:
+		return NewTypeInfo(TYPE_BIGINT)
+	case *arrow.Uint8Type:
+		return NewTypeInfo(TYPE_UTINYINT)
+	case *arrow.Uint16Type:
+		return NewTypeInfo(TYPE_USMALLINT)
+	case *arrow.Uint32Type:
+		return NewTypeInfo(TYPE_UINTEGER)
+	case *arrow.Uint64Type:
+		return NewTypeInfo(TYPE_UBIGINT)
+	case *arrow.Float32Type:
+		return NewTypeInfo(TYPE_FLOAT)
+	case *arrow.Float64Type:
+		return NewTypeInfo(TYPE_DOUBLE)
+	case *arrow.StringType, *arrow.LargeStringType:
+		return NewTypeInfo(TYPE_VARCHAR)
+	case *arrow.BinaryType, *arrow.LargeBinaryType, *arrow.FixedSizeBinaryType:
+		return NewTypeInfo(TYPE_BLOB)
+	case *arrow.Date32Type, *arrow.Date64Type:
+		return NewTypeInfo(TYPE_DATE)
+	case *arrow.TimestampType:
+		if dt.TimeZone != "" {
+			return NewTypeInfo(TYPE_TIMESTAMP_TZ)
+		}
+		switch dt.Unit {
+		case arrow.Second:
+			return NewTypeInfo(TYPE_TIMESTAMP_S)
+		case arrow.Millisecond:
+			return NewTypeInfo(TYPE_TIMESTAMP_MS)
+		case arrow.Nanosecond:
+			return NewTypeInfo(TYPE_TIMESTAMP_NS)
+		}
+		return NewTypeInfo(TYPE_TIMESTAMP)
+	case *arrow.Time32Type, *arrow.Time64Type:
+		return NewTypeInfo(TYPE_TIME)
+	case *arrow.Decimal128Type:
+		return NewDecimalInfo(uint8(dt.Precision), uint8(dt.Scale))
+	case *arrow.MonthDayNanoIntervalType:
+		return NewTypeInfo(TYPE_INTERVAL)
+	case *arrow.MapType:
+		key, err := arrowTypeInfo(dt.KeyType())
+		if err != nil {
+			return nil, err
+		}
+		value, err := arrowTypeInfo(dt.ItemType())
+		if err != nil {
+			return nil, err
+		}
+		return NewMapInfo(key, value)
+	case *arrow.FixedSizeListType:
+		child, err := arrowTypeInfo(dt.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return NewArrayInfo(child, uint64(dt.Len()))
+	case arrow.ListLikeType:
+		child, err := arrowTypeInfo(dt.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return NewListInfo(child)
+	case *arrow.StructType:
+		if dt.NumFields() == 0 {
+			return nil, unsupportedTypeError(dt.String())
+		}
+		entries := make([]StructEntry, dt.NumFields())
+		for i, field := range dt.Fields() {
+			info, err := arrowTypeInfo(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			if entries[i], err = NewStructEntry(info, field.Name); err != nil {
+				return nil, err
+			}
+		}
+		return NewStructInfo(entries[0], entries[1:]...)
+	case *arrow.DictionaryType:
+		return arrowTypeInfo(dt.ValueType)
+	}
+	return nil, unsupportedTypeError(dt.String())
+}
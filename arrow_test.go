@@ -3,13 +3,22 @@
 package duckdb
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/stretchr/testify/require"
 )
@@ -67,6 +76,61 @@ func TestArrow(t *testing.T) {
 		require.NoError(t, rdr.Err())
 	})
 
+	t.Run("fetch records lazily", func(t *testing.T) {
+		c := newConnectorWrapper(t, ``, nil)
+		defer closeConnectorWrapper(t, c)
+
+		innerConn := openDriverConnWrapper(t, c)
+		defer closeDriverConnWrapper(t, &innerConn)
+
+		ar, err := NewArrowFromConn(innerConn)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		rdr, err := ar.QueryContext(ctx, `SELECT * FROM generate_series(1, 10000)`)
+		require.NoError(t, err)
+		rdr.Retain()
+		rdr.Release()
+		defer rdr.Release()
+
+		require.True(t, rdr.Next())
+		rec := rdr.Record()
+		require.Equal(t, int64(GetDataChunkCapacity()), rec.NumRows())
+		require.Equal(t, "1", rec.Column(0).ValueStr(0))
+
+		// The reader stops, if the context is done between two records.
+		cancel()
+		require.False(t, rdr.Next())
+		require.Nil(t, rdr.Record())
+		require.ErrorIs(t, rdr.Err(), context.Canceled)
+		require.False(t, rdr.Next())
+
+		// The connection is usable after the reader stopped.
+		_, err = innerConn.(*Conn).ExecContext(context.Background(), `SELECT 1`, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("interrupt the execution", func(t *testing.T) {
+		c := newConnectorWrapper(t, ``, nil)
+		defer closeConnectorWrapper(t, c)
+
+		innerConn := openDriverConnWrapper(t, c)
+		defer closeDriverConnWrapper(t, &innerConn)
+
+		ar, err := NewArrowFromConn(innerConn)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = ar.QueryContext(ctx, `SELECT count(*) FROM range(1000000000000)`)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// The interrupt does not affect the next query on the connection.
+		_, err = innerConn.(*Conn).ExecContext(context.Background(), `SELECT 1`, nil)
+		require.NoError(t, err)
+	})
+
 	t.Run("query table and filter results", func(t *testing.T) {
 		err := conn.Raw(func(driverConn any) error {
 			innerConn, ok := driverConn.(driver.Conn)
@@ -242,3 +306,836 @@ func TestArrowClosedConn(t *testing.T) {
 	})
 	require.Error(t, err)
 }
+
+func TestArrowRegisterTable(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	ctx := context.Background()
+	var ar *Arrow
+	err := conn.Raw(func(driverConn any) error {
+		var innerErr error
+		ar, innerErr = NewArrowFromConn(driverConn.(driver.Conn))
+		require.NoError(t, innerErr)
+
+		// The records span multiple data chunks.
+		rec1 := newAppenderArrowRecord(0, 3000)
+		defer rec1.Release()
+		rec2 := newAppenderArrowRecord(3000, 100)
+		defer rec2.Release()
+		require.NoError(t, ar.RegisterRecords([]arrow.Record{rec1, rec2}, "recs"))
+
+		tbl := array.NewTableFromRecords(rec1.Schema(), []arrow.Record{rec1, rec2})
+		defer tbl.Release()
+		require.NoError(t, ar.RegisterTable(tbl, "tbl"))
+
+		emptyTbl := array.NewTableFromRecords(rec1.Schema(), nil)
+		defer emptyTbl.Release()
+		require.NoError(t, ar.RegisterTable(emptyTbl, "empty_tbl"))
+
+		require.Error(t, ar.RegisterRecords([]arrow.Record{rec1}, "tbl"))
+		testError(t, ar.RegisterRecords(nil, "empty"), errArrowNoRecords.Error())
+
+		other := array.NewRecord(arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int32}}, nil), []arrow.Array{}, 0)
+		defer other.Release()
+		testError(t, ar.RegisterRecords([]arrow.Record{rec1, other}, "mismatch"), errArrowSchemaMismatch.Error(), invalidInputErrMsg)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The views can be scanned repeatedly.
+	for _, name := range []string{"recs", "tbl", "recs"} {
+		var count, names, sumID, sumA int64
+		var tag string
+		var attr int32
+		query := fmt.Sprintf(`SELECT count(*), count(name), sum(id), sum(meta.a), max(tags[2]), max(attrs['k']) FROM %s`, name)
+		err = conn.QueryRowContext(ctx, query).Scan(&count, &names, &sumID, &sumA, &tag, &attr)
+		require.NoError(t, err)
+		require.Equal(t, int64(3100), count)
+		require.Equal(t, int64(3100-620), names)
+		require.Equal(t, int64(3099*3100/2), sumID)
+		require.Equal(t, sumID, sumA)
+		require.Equal(t, "999", tag)
+		require.Equal(t, int32(3099), attr)
+	}
+
+	var emptyCount int64
+	require.NoError(t, conn.QueryRowContext(ctx, `SELECT count(*) FROM empty_tbl`).Scan(&emptyCount))
+	require.Zero(t, emptyCount)
+
+	// The view can be scanned multiple times in parallel by one query.
+	_, err = conn.ExecContext(ctx, `SET threads = 4`)
+	require.NoError(t, err)
+	var count int64
+	err = conn.QueryRowContext(ctx, `SELECT count(*) FROM recs a JOIN tbl b USING (id) WHERE a.ts = b.ts`).Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, int64(3100), count)
+
+	// Only the projected columns are scanned.
+	var name string
+	var amount float64
+	err = conn.QueryRowContext(ctx, `SELECT name, amount FROM recs WHERE id = 3001`).Scan(&name, &amount)
+	require.NoError(t, err)
+	require.Equal(t, "name3001", name)
+	require.Equal(t, 30.01, amount)
+
+	err = conn.Raw(func(driverConn any) error {
+		require.NoError(t, ar.Unregister("recs"))
+		require.NoError(t, ar.Unregister("recs"))
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = conn.QueryContext(ctx, `SELECT * FROM recs`)
+	require.Error(t, err)
+	err = conn.QueryRowContext(ctx, `SELECT count(*) FROM tbl`).Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, int64(3100), count)
+}
+
+func TestArrowRegisterTableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arrow.db")
+	db := openDbWrapper(t, path)
+	conn := openConnWrapper(t, db, context.Background())
+
+	ctx := context.Background()
+	_, err := conn.ExecContext(ctx, `CREATE TABLE persistent (i INT)`)
+	require.NoError(t, err)
+
+	err = conn.Raw(func(driverConn any) error {
+		ar, innerErr := NewArrowFromConn(driverConn.(driver.Conn))
+		require.NoError(t, innerErr)
+		rec := newAppenderArrowRecord(0, 10)
+		defer rec.Release()
+		require.NoError(t, ar.RegisterRecords([]arrow.Record{rec}, "recs"))
+
+		// The view is temporary, so it can shadow a table of the database file.
+		require.NoError(t, ar.RegisterRecords([]arrow.Record{rec}, "persistent"))
+		require.NoError(t, ar.Unregister("persistent"))
+		return nil
+	})
+	require.NoError(t, err)
+
+	var count int64
+	require.NoError(t, conn.QueryRowContext(ctx, `SELECT count(*) FROM recs`).Scan(&count))
+	require.Equal(t, int64(10), count)
+	require.NoError(t, conn.QueryRowContext(ctx, `SELECT count(*) FROM persistent`).Scan(&count))
+	require.Zero(t, count)
+
+	// Other connections do not see the view.
+	other := openConnWrapper(t, db, context.Background())
+	_, err = other.QueryContext(ctx, `SELECT * FROM recs`)
+	require.Error(t, err)
+	closeConnWrapper(t, other)
+	closeConnWrapper(t, conn)
+	closeDbWrapper(t, db)
+
+	// The view does not persist in the database file.
+	db = openDbWrapper(t, path)
+	defer closeDbWrapper(t, db)
+	require.NoError(t, db.QueryRow(`SELECT count(*) FROM duckdb_views() WHERE view_name = 'recs'`).Scan(&count))
+	require.Zero(t, count)
+	require.NoError(t, db.QueryRow(`SELECT count(*) FROM persistent`).Scan(&count))
+	require.Zero(t, count)
+}
+
+func TestArrowIPC(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	ctx := context.Background()
+	err := conn.Raw(func(driverConn any) error {
+		ar, innerErr := NewArrowFromConn(driverConn.(driver.Conn))
+		require.NoError(t, innerErr)
+
+		var buf bytes.Buffer
+		query := `SELECT i, 'v' || i AS s, [i, i + 1] AS l FROM range(?) t(i)`
+		require.NoError(t, ar.WriteIPC(ctx, &buf, query, 5000))
+
+		// The stream can be read by other Arrow implementations.
+		reader, innerErr := ipc.NewReader(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, innerErr)
+		rows := int64(0)
+		for reader.Next() {
+			rows += reader.Record().NumRows()
+		}
+		require.NoError(t, reader.Err())
+		require.Equal(t, []string{"i", "s", "l"}, []string{
+			reader.Schema().Field(0).Name, reader.Schema().Field(1).Name, reader.Schema().Field(2).Name,
+		})
+		reader.Release()
+		require.Equal(t, int64(5000), rows)
+
+		require.NoError(t, ar.RegisterIPC(&buf, "ipc"))
+
+		var empty bytes.Buffer
+		require.NoError(t, ar.WriteIPC(ctx, &empty, `SELECT 1 AS i WHERE false`))
+		require.NoError(t, ar.RegisterIPC(&empty, "empty"))
+
+		require.Error(t, ar.RegisterIPC(bytes.NewReader([]byte("no ipc")), "invalid"))
+		require.Error(t, ar.WriteIPC(ctx, &buf, `SELECT * FROM missing`))
+		return nil
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		var count, sum int64
+		var s string
+		err = conn.QueryRowContext(ctx, `SELECT count(*), sum(l[2]), max(s) FROM ipc`).Scan(&count, &sum, &s)
+		require.NoError(t, err)
+		require.Equal(t, int64(5000), count)
+		require.Equal(t, int64(5000*5001/2), sum)
+		require.Equal(t, "v999", s)
+	}
+
+	var count int64
+	require.NoError(t, conn.QueryRowContext(ctx, `SELECT count(*) FROM empty`).Scan(&count))
+	require.Equal(t, int64(0), count)
+}
+
+func TestArrowOptions(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	ctx := context.Background()
+	query := `SELECT 'a' || i AS s, [i] AS l FROM range(3000) t(i)`
+	enabled := true
+	err := conn.Raw(func(driverConn any) error {
+		dc := driverConn.(driver.Conn)
+
+		// The connection settings.
+		ar, innerErr := NewArrowFromConn(dc)
+		require.NoError(t, innerErr)
+		r, innerErr := ar.QueryContext(ctx, query)
+		require.NoError(t, innerErr)
+		require.Equal(t, arrow.STRING, r.Schema().Field(0).Type.ID())
+		require.Equal(t, arrow.LIST, r.Schema().Field(1).Type.ID())
+		r.Release()
+
+		ar, innerErr = NewArrowFromConnWithOptions(dc, ArrowOptions{LargeBufferSize: &enabled})
+		require.NoError(t, innerErr)
+		r, innerErr = ar.QueryContext(ctx, query)
+		require.NoError(t, innerErr)
+		require.Equal(t, arrow.LARGE_STRING, r.Schema().Field(0).Type.ID())
+		require.Equal(t, arrow.LARGE_LIST, r.Schema().Field(1).Type.ID())
+
+		// The records are fetched after the settings have been restored.
+		rows := int64(0)
+		for r.Next() {
+			_, ok := r.Record().Column(0).(*array.LargeString)
+			require.True(t, ok)
+			rows += r.Record().NumRows()
+		}
+		require.NoError(t, r.Err())
+		require.Equal(t, int64(3000), rows)
+		r.Release()
+
+		ar, innerErr = NewArrowFromConnWithOptions(dc, ArrowOptions{StringView: &enabled})
+		require.NoError(t, innerErr)
+		r, innerErr = ar.QueryContext(ctx, query)
+		require.NoError(t, innerErr)
+		require.Equal(t, arrow.STRING_VIEW, r.Schema().Field(0).Type.ID())
+		r.Release()
+
+		// The expected schema ignores the nullability.
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "s", Type: arrow.BinaryTypes.String},
+			{Name: "l", Type: arrow.ListOf(arrow.PrimitiveTypes.Int64)},
+		}, nil)
+		ar, innerErr = NewArrowFromConnWithOptions(dc, ArrowOptions{Schema: schema})
+		require.NoError(t, innerErr)
+		r, innerErr = ar.QueryContext(ctx, query)
+		require.NoError(t, innerErr)
+		r.Release()
+
+		ar, innerErr = NewArrowFromConnWithOptions(dc, ArrowOptions{Schema: schema, LargeBufferSize: &enabled})
+		require.NoError(t, innerErr)
+		_, innerErr = ar.QueryContext(ctx, query)
+		testError(t, innerErr, errArrowSchemaMismatch.Error(), castErrMsg, indexErrMsg)
+
+		_, innerErr = ar.QueryContext(ctx, `SELECT 'a' AS x, [1] AS l`)
+		testError(t, innerErr, errArrowSchemaMismatch.Error(), structFieldErrMsg)
+
+		_, innerErr = ar.QueryContext(ctx, `SELECT 'a' AS s`)
+		testError(t, innerErr, errArrowSchemaMismatch.Error(), columnCountErrMsg)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The previous settings are restored.
+	var largeBuffers, stringViews bool
+	err = conn.QueryRowContext(ctx, `SELECT current_setting('arrow_large_buffer_size'), current_setting('produce_arrow_string_view')`).Scan(&largeBuffers, &stringViews)
+	require.NoError(t, err)
+	require.False(t, largeBuffers)
+	require.False(t, stringViews)
+}
+
+func TestArrowOptionsConnectionSettings(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	ctx := context.Background()
+	_, err := conn.ExecContext(ctx, `SET arrow_large_buffer_size = true`)
+	require.NoError(t, err)
+
+	query := `SELECT 'a' || i AS s, [i] AS l FROM range(10) t(i)`
+	enabled, disabled := true, false
+	err = conn.Raw(func(driverConn any) error {
+		dc := driverConn.(driver.Conn)
+
+		// The nil options keep the connection settings.
+		ar, innerErr := NewArrowFromConnWithOptions(dc, ArrowOptions{ListView: &enabled})
+		require.NoError(t, innerErr)
+		r, innerErr := ar.QueryContext(ctx, query)
+		require.NoError(t, innerErr)
+		require.Equal(t, arrow.LARGE_STRING, r.Schema().Field(0).Type.ID())
+		require.Equal(t, arrow.LARGE_LIST_VIEW, r.Schema().Field(1).Type.ID())
+		r.Release()
+
+		ar, innerErr = NewArrowFromConnWithOptions(dc, ArrowOptions{})
+		require.NoError(t, innerErr)
+		r, innerErr = ar.QueryContext(ctx, query)
+		require.NoError(t, innerErr)
+		require.Equal(t, arrow.LARGE_STRING, r.Schema().Field(0).Type.ID())
+		r.Release()
+
+		// A false option overrides the connection setting.
+		ar, innerErr = NewArrowFromConnWithOptions(dc, ArrowOptions{LargeBufferSize: &disabled})
+		require.NoError(t, innerErr)
+		r, innerErr = ar.QueryContext(ctx, query)
+		require.NoError(t, innerErr)
+		require.Equal(t, arrow.STRING, r.Schema().Field(0).Type.ID())
+		require.Equal(t, arrow.LIST, r.Schema().Field(1).Type.ID())
+		r.Release()
+		return nil
+	})
+	require.NoError(t, err)
+
+	var largeBuffers, listViews bool
+	err = conn.QueryRowContext(ctx, `SELECT current_setting('arrow_large_buffer_size'), current_setting('arrow_output_list_view')`).Scan(&largeBuffers, &listViews)
+	require.NoError(t, err)
+	require.True(t, largeBuffers)
+	require.False(t, listViews)
+}
+
+func TestArrowScalarUDF(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	intInfo, err := NewTypeInfo(TYPE_INTEGER)
+	require.NoError(t, err)
+	bigintInfo, err := NewTypeInfo(TYPE_BIGINT)
+	require.NoError(t, err)
+	varcharInfo, err := NewTypeInfo(TYPE_VARCHAR)
+	require.NoError(t, err)
+	anyInfo, err := NewTypeInfo(TYPE_ANY)
+	require.NoError(t, err)
+	listInfo, err := NewListInfo(varcharInfo)
+	require.NoError(t, err)
+
+	add, err := NewArrowScalarFunc(ScalarFuncConfig{
+		InputTypeInfos: []TypeInfo{intInfo, intInfo},
+		ResultTypeInfo: bigintInfo,
+	}, func(input arrow.Record) (arrow.Array, error) {
+		a := input.Column(0).(*array.Int32).Int32Values()
+		b := input.Column(1).(*array.Int32).Int32Values()
+		builder := array.NewInt64Builder(memory.DefaultAllocator)
+		defer builder.Release()
+		for i := range a {
+			builder.Append(int64(a[i]) + int64(b[i]))
+		}
+		return builder.NewArray(), nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, RegisterScalarUDF(conn, "arrow_add", add))
+
+	tokenize, err := NewArrowScalarFunc(ScalarFuncConfig{
+		InputTypeInfos: []TypeInfo{varcharInfo},
+		ResultTypeInfo: listInfo,
+	}, func(input arrow.Record) (arrow.Array, error) {
+		values := input.Column(0).(*array.String)
+		builder := array.NewListBuilder(memory.DefaultAllocator, arrow.BinaryTypes.String)
+		defer builder.Release()
+		for i := 0; i < values.Len(); i++ {
+			builder.Append(true)
+			builder.ValueBuilder().(*array.StringBuilder).AppendValues(strings.Fields(values.Value(i)), nil)
+		}
+		return builder.NewArray(), nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, RegisterScalarUDF(conn, "tokenize", tokenize))
+
+	// arrow_str returns the Arrow type and the value of its input.
+	str, err := NewArrowScalarFunc(ScalarFuncConfig{
+		InputTypeInfos:      []TypeInfo{anyInfo},
+		ResultTypeInfo:      varcharInfo,
+		SpecialNullHandling: true,
+	}, func(input arrow.Record) (arrow.Array, error) {
+		column := input.Column(0)
+		builder := array.NewStringBuilder(memory.DefaultAllocator)
+		defer builder.Release()
+		for i := 0; i < column.Len(); i++ {
+			builder.Append(column.DataType().String() + " " + column.ValueStr(i))
+		}
+		return builder.NewArray(), nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, RegisterScalarUDF(conn, "arrow_str", str))
+
+	ctx := context.Background()
+	var sum, nulls int64
+	err = conn.QueryRowContext(ctx, `SELECT sum(arrow_add(i::INTEGER, 2)), count(*) FILTER (arrow_add(NULLIF(i, 7)::INTEGER, 1) IS NULL)
+		FROM range(5000) t(i)`).Scan(&sum, &nulls)
+	require.NoError(t, err)
+	require.Equal(t, int64(4999*5000/2+2*5000), sum)
+	require.Equal(t, int64(1), nulls)
+
+	var tokens string
+	err = conn.QueryRowContext(ctx, `SELECT tokenize('geo spatial  tokens')::VARCHAR`).Scan(&tokens)
+	require.NoError(t, err)
+	require.Equal(t, "[geo, spatial, tokens]", tokens)
+
+	tests := map[string]string{
+		`NULL::INTEGER`:                          "int32 (null)",
+		`true`:                                   "bool true",
+		`42::UTINYINT`:                           "uint8 42",
+		`1.5::DOUBLE`:                            "float64 1.5",
+		`'abc'`:                                  "utf8 abc",
+		`'abc'::BLOB`:                            "binary YWJj",
+		`DATE '2024-01-02'`:                      "date32 2024-01-02",
+		`TIMESTAMP '2024-01-02 03:04:05'`:        "timestamp[us] 2024-01-02 03:04:05Z",
+		`TIMESTAMP_MS '2024-01-02 03:04:05.006'`: "timestamp[ms] 2024-01-02 03:04:05.006Z",
+		`TIME '01:02:03'`:                        "time64[us] 01:02:03.000000",
+		`INTERVAL 1 DAY`:                         `month_day_nano_interval {"months":0,"days":1,"nanoseconds":0}`,
+		`12.34::DECIMAL(10, 2)`:                  "decimal(10, 2) 12.34",
+		`[1, NULL, 3]`:                           "list<item: int32, nullable> [1,null,3]",
+		`[1, 2]::INTEGER[2]`:                     "fixed_size_list<item: int32, nullable>[2] [1,2]",
+		`{'a': 1, 'b': 'x'}`:                     `struct<a: int32, b: utf8> {"a":1,"b":"x"}`,
+		`MAP {'k': 1}`:                           `map<utf8, int32, items_nullable> [{"key":"k","value":1}]`,
+	}
+	for expr, expected := range tests {
+		var actual string
+		err = conn.QueryRowContext(ctx, `SELECT arrow_str(`+expr+`)`).Scan(&actual)
+		require.NoError(t, err, expr)
+		require.Equal(t, expected, actual, expr)
+	}
+
+	// The results must match the rows and the result type.
+	wrongLength, err := NewArrowScalarFunc(ScalarFuncConfig{ResultTypeInfo: intInfo}, func(arrow.Record) (arrow.Array, error) {
+		return array.NewNull(2), nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, RegisterScalarUDF(conn, "wrong_length", wrongLength))
+	_, err = conn.ExecContext(ctx, `SELECT wrong_length()`)
+	require.ErrorContains(t, err, invalidInputErrMsg)
+
+	wrongType, err := NewArrowScalarFunc(ScalarFuncConfig{InputTypeInfos: []TypeInfo{varcharInfo}, ResultTypeInfo: intInfo},
+		func(input arrow.Record) (arrow.Array, error) {
+			input.Column(0).Retain()
+			return input.Column(0), nil
+		})
+	require.NoError(t, err)
+	require.NoError(t, RegisterScalarUDF(conn, "wrong_type", wrongType))
+	_, err = conn.ExecContext(ctx, `SELECT wrong_type('a')`)
+	require.ErrorContains(t, err, castErrMsg)
+
+	failing, err := NewArrowScalarFunc(ScalarFuncConfig{ResultTypeInfo: intInfo}, func(arrow.Record) (arrow.Array, error) {
+		return nil, errors.New("kernel failed")
+	})
+	require.NoError(t, err)
+	require.NoError(t, RegisterScalarUDF(conn, "failing", failing))
+	_, err = conn.ExecContext(ctx, `SELECT failing()`)
+	require.ErrorContains(t, err, "kernel failed")
+
+	_, err = NewArrowScalarFunc(ScalarFuncConfig{ResultTypeInfo: intInfo}, nil)
+	testError(t, err, errAPI.Error(), errScalarUDFNoExecutor.Error())
+}
+
+// countingRecordReader counts the calls to Release.
+type countingRecordReader struct {
+	array.RecordReader
+	released *atomic.Int64
+}
+
+func (r *countingRecordReader) Release() {
+	r.released.Add(1)
+	r.RecordReader.Release()
+}
+
+func TestArrowTableUDF(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	intInfo, err := NewTypeInfo(TYPE_INTEGER)
+	require.NoError(t, err)
+
+	var binds, released atomic.Int64
+	err = RegisterArrowTableUDF(conn, "arrow_source", TableFunctionConfig{
+		Arguments: []TypeInfo{intInfo},
+	}, func(named map[string]any, args ...any) (array.RecordReader, error) {
+		n := int(args[0].(int32))
+		if n < 0 {
+			return nil, errors.New("negative row count")
+		}
+		binds.Add(1)
+
+		// A large record, an empty record, and a small record.
+		large := newAppenderArrowRecord(0, n)
+		defer large.Release()
+		empty := newAppenderArrowRecord(n, 0)
+		defer empty.Release()
+		small := newAppenderArrowRecord(n, 10)
+		defer small.Release()
+
+		reader, err := array.NewRecordReader(large.Schema(), []arrow.Record{large, empty, small})
+		if err != nil {
+			return nil, err
+		}
+		return &countingRecordReader{RecordReader: reader, released: &released}, nil
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	var count, sumID, names int64
+	err = conn.QueryRowContext(ctx, `SELECT count(*), sum(id), count(name) FROM arrow_source(5000)`).Scan(&count, &sumID, &names)
+	require.NoError(t, err)
+	require.Equal(t, int64(5010), count)
+	require.Equal(t, int64(5009*5010/2), sumID)
+	require.Equal(t, int64(5010-1002), names)
+	require.Equal(t, int64(1), released.Load())
+
+	// Only the projected columns are copied.
+	var name string
+	var tags string
+	var a int32
+	err = conn.QueryRowContext(ctx, `SELECT name, tags::VARCHAR, meta.a FROM arrow_source(3) WHERE id = 11`).Scan(&name, &tags, &a)
+	require.NoError(t, err)
+	require.Equal(t, "name11", name)
+	require.Equal(t, "[x, 11]", tags)
+	require.Equal(t, int32(11), a)
+
+	// The reader is released, if the query does not consume all records.
+	released.Store(0)
+	err = conn.QueryRowContext(ctx, `SELECT id FROM arrow_source(5000) LIMIT 1`).Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), released.Load())
+
+	// A prepared statement binds a new reader for each execution.
+	binds.Store(0)
+	released.Store(0)
+	stmt, err := conn.PrepareContext(ctx, `SELECT count(*) FROM arrow_source(100)`)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, stmt.QueryRowContext(ctx).Scan(&count))
+		require.Equal(t, int64(110), count)
+	}
+	require.NoError(t, stmt.Close())
+	require.GreaterOrEqual(t, binds.Load(), int64(2))
+	require.Equal(t, binds.Load(), released.Load())
+
+	_, err = conn.ExecContext(ctx, `SELECT * FROM arrow_source(-1)`)
+	require.ErrorContains(t, err, "negative row count")
+
+	// The reader of each execution must have the schema of the first reader.
+	var changingBinds atomic.Int64
+	err = RegisterArrowTableUDF(conn, "changing_source", TableFunctionConfig{}, func(map[string]any, ...any) (array.RecordReader, error) {
+		if changingBinds.Add(1) == 1 {
+			rec := newAppenderArrowRecord(0, 1)
+			defer rec.Release()
+			return array.NewRecordReader(rec.Schema(), []arrow.Record{rec})
+		}
+		other := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int32}}, nil)
+		return array.NewRecordReader(other, nil)
+	})
+	require.NoError(t, err)
+	stmt, err = conn.PrepareContext(ctx, `SELECT count(*) FROM changing_source()`)
+	require.NoError(t, err)
+	require.NoError(t, stmt.QueryRowContext(ctx).Scan(&count))
+	err = stmt.QueryRowContext(ctx).Scan(&count)
+	require.ErrorContains(t, err, errArrowSchemaMismatch.Error())
+	require.NoError(t, stmt.Close())
+
+	err = RegisterArrowTableUDF(conn, "no_bind", TableFunctionConfig{}, nil)
+	testError(t, err, errAPI.Error(), errTableUDFMissingBindArgs.Error())
+}
+
+func newAppenderArrowRecord(offset int, n int) arrow.Record {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "small", Type: arrow.PrimitiveTypes.Int16, Nullable: true},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+		{Name: "meta", Type: arrow.StructOf(
+			arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+			arrow.Field{Name: "b", Type: arrow.BinaryTypes.String},
+		)},
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32},
+		{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Millisecond}},
+		{Name: "amount", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}},
+		{Name: "attrs", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int32)},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+
+	for i := offset; i < offset+n; i++ {
+		b.Field(0).(*array.Int64Builder).Append(int64(i))
+		if i%5 == 0 {
+			b.Field(1).AppendNull()
+			b.Field(2).AppendNull()
+		} else {
+			b.Field(1).(*array.Int16Builder).Append(int16(i % 1000))
+			b.Field(2).(*array.StringBuilder).Append(fmt.Sprintf("name%d", i))
+		}
+
+		tags := b.Field(3).(*array.ListBuilder)
+		tags.Append(true)
+		tags.ValueBuilder().(*array.StringBuilder).AppendValues([]string{"x", fmt.Sprint(i)}, nil)
+
+		meta := b.Field(4).(*array.StructBuilder)
+		meta.Append(true)
+		meta.FieldBuilder(0).(*array.Int32Builder).Append(int32(i))
+		meta.FieldBuilder(1).(*array.StringBuilder).Append("b")
+
+		b.Field(5).(*array.Date32Builder).Append(arrow.Date32FromTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+		b.Field(6).(*array.TimestampBuilder).Append(arrow.Timestamp(int64(i) * 1000))
+		b.Field(7).(*array.Decimal128Builder).Append(decimal128.FromI64(int64(i)))
+
+		attrs := b.Field(8).(*array.MapBuilder)
+		attrs.Append(true)
+		attrs.KeyBuilder().(*array.StringBuilder).Append("k")
+		attrs.ItemBuilder().(*array.Int32Builder).Append(int32(i))
+	}
+	return b.NewRecord()
+}
+
+func TestAppenderAppendRecord(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (
+		id BIGINT,
+		small INTEGER,
+		name VARCHAR,
+		tags VARCHAR[],
+		meta STRUCT(a BIGINT, b VARCHAR),
+		day DATE,
+		ts TIMESTAMP,
+		amount DECIMAL(12, 3),
+		attrs MAP(VARCHAR, INTEGER)
+	)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	// Cross the chunk boundaries of the appender.
+	rec := newAppenderArrowRecord(0, 3000)
+	defer rec.Release()
+	require.NoError(t, a.AppendRow(int64(-1), nil, nil, nil, nil, nil, nil, nil, nil))
+	require.NoError(t, a.AppendRecord(rec))
+
+	// Append a slice of a record.
+	rec2 := newAppenderArrowRecord(3000, 20)
+	defer rec2.Release()
+	slice := rec2.NewSlice(10, 20)
+	defer slice.Release()
+	require.NoError(t, a.AppendRecord(slice))
+	require.NoError(t, a.Flush())
+
+	var count, nullCount, sum int64
+	err := db.QueryRow(`SELECT count(*), count(*) FILTER (name IS NULL), sum(id) FROM test WHERE id >= 0`).Scan(&count, &nullCount, &sum)
+	require.NoError(t, err)
+	require.Equal(t, int64(3010), count)
+	require.Equal(t, int64(602), nullCount)
+	require.Equal(t, int64(2999*3000/2+(3010+3019)*10/2), sum)
+
+	var small int32
+	var name string
+	var tags []any
+	var meta map[string]any
+	var day, ts time.Time
+	var amount Decimal
+	var attrs Map
+	err = db.QueryRow(`SELECT small, name, tags, meta, day, ts, amount, attrs FROM test WHERE id = 2049`).Scan(
+		&small, &name, &tags, &meta, &day, &ts, &amount, &attrs)
+	require.NoError(t, err)
+	require.Equal(t, int32(49), small)
+	require.Equal(t, "name2049", name)
+	require.Equal(t, []any{"x", "2049"}, tags)
+	require.Equal(t, map[string]any{"a": int64(2049), "b": "b"}, meta)
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), day)
+	require.Equal(t, time.UnixMilli(2049*1000).UTC(), ts)
+	require.Equal(t, "20.49", amount.String())
+	require.Equal(t, Map{"k": int32(2049)}, attrs)
+}
+
+func TestAppenderAppendRecordMapping(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (id INTEGER, kind VARCHAR DEFAULT 'event', v DOUBLE)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	newRecord := func(names ...string) arrow.Record {
+		fields := []arrow.Field{{Name: names[0], Type: arrow.PrimitiveTypes.Float64}, {Name: names[1], Type: arrow.PrimitiveTypes.Int32}}
+		b := array.NewRecordBuilder(memory.DefaultAllocator, arrow.NewSchema(fields, nil))
+		defer b.Release()
+		b.Field(0).(*array.Float64Builder).AppendValues([]float64{1.5, 2.5}, nil)
+		b.Field(1).(*array.Int32Builder).AppendValues([]int32{1, 2}, nil)
+		return b.NewRecord()
+	}
+
+	// By name, with a DEFAULT value for the omitted column.
+	rec := newRecord("V", "id")
+	defer rec.Release()
+	require.NoError(t, a.AppendRecord(rec))
+
+	// By position.
+	b := array.NewRecordBuilder(memory.DefaultAllocator, arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int16},
+		{Name: "b", Type: arrow.BinaryTypes.String},
+		{Name: "c", Type: arrow.PrimitiveTypes.Float32},
+	}, nil))
+	defer b.Release()
+	b.Field(0).(*array.Int16Builder).Append(3)
+	b.Field(1).(*array.StringBuilder).Append("custom")
+	b.Field(2).(*array.Float32Builder).Append(3.5)
+	byPosition := b.NewRecord()
+	defer byPosition.Release()
+
+	rdr, err := array.NewRecordReader(byPosition.Schema(), []arrow.Record{byPosition})
+	require.NoError(t, err)
+	defer rdr.Release()
+	require.NoError(t, a.AppendRecordReader(rdr))
+	require.NoError(t, a.Flush())
+
+	var res string
+	err = db.QueryRow(`SELECT string_agg(id || ':' || kind || ':' || v, ',' ORDER BY id) FROM test`).Scan(&res)
+	require.NoError(t, err)
+	require.Equal(t, "1:event:1.5,2:event:2.5,3:custom:3.5", res)
+
+	// Errors.
+	mismatch := newRecord("x", "y")
+	defer mismatch.Release()
+	err = a.AppendRecord(mismatch)
+	testError(t, err, errAppenderAppendRecord.Error(), columnCountErrMsg)
+
+	b2 := array.NewRecordBuilder(memory.DefaultAllocator, arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.BinaryTypes.String},
+	}, nil))
+	defer b2.Release()
+	b2.Field(0).(*array.StringBuilder).Append("x")
+	wrongType := b2.NewRecord()
+	defer wrongType.Release()
+	err = a.AppendRecord(wrongType)
+	testError(t, err, errAppenderAppendRecord.Error(), castErrMsg)
+
+	// Narrowing numeric conversions.
+	for _, dt := range []arrow.DataType{arrow.PrimitiveTypes.Int64, arrow.PrimitiveTypes.Uint32, arrow.PrimitiveTypes.Float32} {
+		b3 := array.NewRecordBuilder(memory.DefaultAllocator, arrow.NewSchema([]arrow.Field{{Name: "id", Type: dt}}, nil))
+		b3.Field(0).AppendNull()
+		narrowing := b3.NewRecord()
+		err = a.AppendRecord(narrowing)
+		testError(t, err, errAppenderAppendRecord.Error(), castErrMsg)
+		narrowing.Release()
+		b3.Release()
+	}
+}
+
+func TestAppenderAppendRecordLossless(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (d DECIMAL(12, 3), i INTERVAL)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	newRecord := func(dt *arrow.Decimal128Type, nanos []int64) arrow.Record {
+		b := array.NewRecordBuilder(memory.DefaultAllocator, arrow.NewSchema([]arrow.Field{
+			{Name: "d", Type: dt},
+			{Name: "i", Type: arrow.FixedWidthTypes.MonthDayNanoInterval},
+		}, nil))
+		defer b.Release()
+		for _, n := range nanos {
+			b.Field(0).(*array.Decimal128Builder).Append(decimal128.FromI64(n))
+			b.Field(1).(*array.MonthDayNanoIntervalBuilder).Append(arrow.MonthDayNanoInterval{Months: 1, Days: 2, Nanoseconds: n})
+		}
+		return b.NewRecord()
+	}
+
+	// A DECIMAL with more fractional or integer digits than the column.
+	for _, dt := range []*arrow.Decimal128Type{{Precision: 10, Scale: 4}, {Precision: 18, Scale: 3}} {
+		rec := newRecord(dt, []int64{1000})
+		err := a.AppendRecord(rec)
+		testError(t, err, errAppenderAppendRecord.Error(), castErrMsg)
+		rec.Release()
+	}
+
+	// An interval with sub-microsecond nanoseconds.
+	rec := newRecord(&arrow.Decimal128Type{Precision: 11, Scale: 2}, []int64{3000, 1500})
+	defer rec.Release()
+	err := a.AppendRecord(rec)
+	testError(t, err, errAppenderAppendRecord.Error(), castErrMsg)
+
+	var rejected []RejectedRow
+	a.SetRejectHandler(func(row RejectedRow) {
+		rejected = append(rejected, row)
+	})
+	require.NoError(t, a.AppendRecord(rec))
+	require.NoError(t, a.Flush())
+	require.Len(t, rejected, 1)
+	require.Equal(t, 1, rejected[0].Column)
+	require.ErrorContains(t, rejected[0].Err, castErrMsg)
+
+	var d string
+	var i Interval
+	require.NoError(t, db.QueryRow(`SELECT d::VARCHAR, i FROM test`).Scan(&d, &i))
+	require.Equal(t, "30.000", d)
+	require.Equal(t, Interval{Months: 1, Days: 2, Micros: 3}, i)
+}
+
+func TestAppenderAppendRecordReject(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TYPE mood AS ENUM ('x', 'y');
+		CREATE TABLE test (a BIGINT, e mood)`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	b := array.NewRecordBuilder(memory.DefaultAllocator, arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "e", Type: arrow.BinaryTypes.String},
+	}, nil))
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{0, 1, 2}, []bool{false, true, true})
+	b.Field(1).(*array.StringBuilder).AppendValues([]string{"x", "bad", "y"}, nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	// A failed record does not leave NULL values behind.
+	err := a.AppendRecord(rec)
+	testError(t, err, errAppenderAppendRecord.Error())
+	require.NoError(t, a.AppendRow(int64(7), "x"))
+
+	var rejected []RejectedRow
+	a.SetRejectHandler(func(row RejectedRow) {
+		rejected = append(rejected, row)
+	})
+	require.NoError(t, a.AppendRecord(rec))
+	require.NoError(t, a.AppendRow(int64(8), "y"))
+	require.NoError(t, a.Flush())
+
+	require.Len(t, rejected, 1)
+	require.Equal(t, int64(2), rejected[0].Row)
+	require.Equal(t, 1, rejected[0].Column)
+	require.Equal(t, []driver.Value{int64(1), "bad"}, rejected[0].Values)
+	require.Equal(t, int64(1), a.Stats().RowsRejected)
+
+	var res string
+	err = db.QueryRow(`SELECT string_agg(coalesce(a::VARCHAR, 'NULL') || ':' || e, ',' ORDER BY rowid) FROM test`).Scan(&res)
+	require.NoError(t, err)
+	require.Equal(t, "7:x,NULL:x,2:y,8:y", res)
+}
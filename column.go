@@ -0,0 +1,288 @@
+package duckdb
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/marcboeker/go-duckdb/mapping"
+)
+
+// fixedWidthType are the Go types that share their memory layout with a DuckDB vector.
+type fixedWidthType interface {
+	bool | int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64 | float32 | float64
+}
+
+// columnValueType are the Go types that SetColumn writes into a column.
+type columnValueType interface {
+	fixedWidthType | string | []byte
+}
+
+// stringTSize is the size of a duckdb_string_t.
+const stringTSize = 16
+
+// stringTInlineLength is the maximum length of a string inlined into its duckdb_string_t.
+const stringTInlineLength = 12
+
+// Validity is the validity mask of a column in a data chunk.
+// A row is valid, if it is not NULL.
+type Validity struct {
+	// maskPtr is nil, if all rows are valid.
+	maskPtr unsafe.Pointer
+	// size is the number of rows in the mask.
+	size int
+}
+
+// NewValidity returns a Validity of size rows, which are all valid.
+// Use SetNull to mark rows as NULL, e.g., before passing it to SetColumn.
+func NewValidity(size int) Validity {
+	if size <= 0 {
+		return Validity{}
+	}
+	mask := make([]uint64, (size+63)/64)
+	for i := range mask {
+		mask[i] = ^uint64(0)
+	}
+	return Validity{maskPtr: unsafe.Pointer(&mask[0]), size: size}
+}
+
+// IsValid returns true, if the row at rowIdx is not NULL.
+func (v Validity) IsValid(rowIdx int) bool {
+	if v.maskPtr == nil {
+		return true
+	}
+	return v.entries()[rowIdx/64]&(uint64(1)<<(rowIdx%64)) != 0
+}
+
+// AllValid returns true, if the column has no validity mask, i.e., none of its rows are NULL.
+// If it returns false, some rows can still be valid, see IsValid.
+func (v Validity) AllValid() bool {
+	return v.maskPtr == nil
+}
+
+// SetNull marks the row at rowIdx as NULL. It panics, if v has no validity mask, see AllValid.
+func (v Validity) SetNull(rowIdx int) {
+	v.entries()[rowIdx/64] &^= uint64(1) << (rowIdx % 64)
+}
+
+// SetValid marks the row at rowIdx as valid. It panics, if v has no validity mask, see AllValid.
+func (v Validity) SetValid(rowIdx int) {
+	v.entries()[rowIdx/64] |= uint64(1) << (rowIdx % 64)
+}
+
+func (v Validity) entries() []uint64 {
+	if v.maskPtr == nil {
+		panic("duckdb: validity mask is nil")
+	}
+	return unsafe.Slice((*uint64)(v.maskPtr), (v.size+63)/64)
+}
+
+// GetColumn returns the values of a column with a fixed-width type without copying them.
+// T must match the physical type of the column, e.g., int64 for BIGINT, or float64 for DOUBLE.
+// DECIMAL columns return their unscaled values, i.e., int16, int32, or int64, depending on their width.
+// ENUM columns return their dictionary indexes. TIMESTAMP and TIME columns return their int64 values
+// in the unit of their type, and DATE columns return the int32 number of days since the epoch.
+// The values of NULL rows are undefined. The slice and the Validity are only valid as long as the chunk.
+// It returns no values for columns that are not projected, see DataChunk.IsProjected.
+func GetColumn[T fixedWidthType](chunk DataChunk, colIdx int) ([]T, Validity, error) {
+	vec, err := chunk.projectedColumn(colIdx)
+	if vec == nil {
+		return nil, Validity{}, err
+	}
+
+	var zero T
+	if vec.physicalType() != fixedWidthTypeOf(zero) {
+		return nil, Validity{}, getError(errAPI, castError(typeToStringMap[vec.Type], reflect.TypeFor[[]T]().String()))
+	}
+
+	size := int(mapping.DataChunkGetSize(chunk.chunk))
+	return unsafe.Slice((*T)(vec.dataPtr), size), Validity{maskPtr: vec.maskPtr, size: size}, nil
+}
+
+// SetColumn writes values into the first len(values) rows of a column. Rows that are not valid in nulls are NULL.
+// A Validity without a mask, e.g., Validity{}, writes no NULL values.
+// For fixed-width types, T must match the physical type of the column, see GetColumn.
+// VARCHAR and BLOB columns accept string and []byte values.
+// SetColumn does not change the size of the chunk, see DataChunk.SetSize.
+// It does nothing for columns that are not projected, see DataChunk.IsProjected.
+func SetColumn[T columnValueType](chunk DataChunk, colIdx int, values []T, nulls Validity) error {
+	vec, err := chunk.projectedColumn(colIdx)
+	if vec == nil {
+		return err
+	}
+	if len(values) > GetDataChunkCapacity() {
+		return getError(errAPI, errVectorSize)
+	}
+	if nulls.maskPtr != nil && nulls.size < len(values) {
+		return getError(errAPI, invalidInputError(fmt.Sprintf("a validity of %d rows", nulls.size), fmt.Sprintf("at least %d rows", len(values))))
+	}
+
+	switch vs := any(values).(type) {
+	case []string:
+		if vec.Type != TYPE_VARCHAR && vec.Type != TYPE_BLOB {
+			return getError(errAPI, castError(reflect.TypeFor[[]T]().String(), typeToStringMap[vec.Type]))
+		}
+		for i, v := range vs {
+			if nulls.IsValid(i) {
+				mapping.VectorAssignStringElementLen(vec.vec, mapping.IdxT(i), unsafe.Slice(unsafe.StringData(v), len(v)))
+			}
+		}
+	case [][]byte:
+		if vec.Type != TYPE_VARCHAR && vec.Type != TYPE_BLOB {
+			return getError(errAPI, castError(reflect.TypeFor[[]T]().String(), typeToStringMap[vec.Type]))
+		}
+		for i, v := range vs {
+			if nulls.IsValid(i) {
+				mapping.VectorAssignStringElementLen(vec.vec, mapping.IdxT(i), v)
+			}
+		}
+	default:
+		var zero T
+		if vec.physicalType() != fixedWidthTypeOf(zero) {
+			return getError(errAPI, castError(reflect.TypeFor[[]T]().String(), typeToStringMap[vec.Type]))
+		}
+		copy(unsafe.Slice((*T)(vec.dataPtr), len(values)), values)
+	}
+
+	vec.setValidity(nulls, len(values))
+	return nil
+}
+
+// StringColumn provides access to a VARCHAR or BLOB column of a data chunk
+// without converting each value to an any.
+type StringColumn struct {
+	dataPtr unsafe.Pointer
+	size    int
+}
+
+// GetStringColumn returns a StringColumn of a VARCHAR or BLOB column.
+// It is only valid as long as the chunk. It has no rows for columns that are not projected, see DataChunk.IsProjected.
+func GetStringColumn(chunk DataChunk, colIdx int) (StringColumn, Validity, error) {
+	vec, err := chunk.projectedColumn(colIdx)
+	if vec == nil {
+		return StringColumn{}, Validity{}, err
+	}
+	if vec.Type != TYPE_VARCHAR && vec.Type != TYPE_BLOB {
+		return StringColumn{}, Validity{}, getError(errAPI, castError(typeToStringMap[vec.Type], reflect.TypeFor[StringColumn]().String()))
+	}
+
+	size := int(mapping.DataChunkGetSize(chunk.chunk))
+	return StringColumn{dataPtr: vec.dataPtr, size: size}, Validity{maskPtr: vec.maskPtr, size: size}, nil
+}
+
+// Len returns the number of rows of the column.
+func (c StringColumn) Len() int {
+	return c.size
+}
+
+// Bytes returns the value of the row at rowIdx without copying it.
+// The returned slice must not be modified, and it is only valid as long as the chunk.
+// The values of NULL rows are undefined.
+func (c StringColumn) Bytes(rowIdx int) []byte {
+	// A duckdb_string_t starts with its uint32 length. Short strings are inlined after the length,
+	// otherwise, a pointer to the string follows a four-byte prefix.
+	strT := unsafe.Add(c.dataPtr, rowIdx*stringTSize)
+	length := *(*uint32)(strT)
+	if length == 0 {
+		return nil
+	}
+	if length <= stringTInlineLength {
+		return unsafe.Slice((*byte)(unsafe.Add(strT, 4)), length)
+	}
+	return unsafe.Slice(*(**byte)(unsafe.Add(strT, 8)), length)
+}
+
+// String returns a copy of the value of the row at rowIdx.
+// The values of NULL rows are undefined.
+func (c StringColumn) String(rowIdx int) string {
+	return string(c.Bytes(rowIdx))
+}
+
+func (chunk *DataChunk) column(colIdx int) (*vector, error) {
+	if colIdx < 0 || colIdx >= len(chunk.columns) {
+		return nil, getError(errAPI, columnCountError(colIdx, len(chunk.columns)))
+	}
+	return &chunk.columns[colIdx], nil
+}
+
+// setValidity copies the validity of the first n rows of nulls into the vector's validity mask.
+func (vec *vector) setValidity(nulls Validity, n int) {
+	if n == 0 || (nulls.maskPtr == nil && vec.maskPtr == nil) {
+		return
+	}
+	if vec.maskPtr == nil {
+		mapping.VectorEnsureValidityWritable(vec.vec)
+		vec.maskPtr = mapping.VectorGetValidity(vec.vec)
+	}
+
+	dst := unsafe.Slice((*uint64)(vec.maskPtr), (n+63)/64)
+	for i := range dst {
+		entry := ^uint64(0)
+		if nulls.maskPtr != nil {
+			entry = nulls.entries()[i]
+		}
+		// Keep the validity of the rows after the n-th row.
+		if remaining := n - i*64; remaining < 64 {
+			keep := uint64(1)<<remaining - 1
+			entry = dst[i]&^keep | entry&keep
+		}
+		dst[i] = entry
+	}
+}
+
+// physicalType returns the type of the values in the vector's data.
+func (vec *vector) physicalType() Type {
+	switch vec.Type {
+	case TYPE_DECIMAL, TYPE_ENUM:
+		return vec.internalType
+	case TYPE_TIMESTAMP, TYPE_TIMESTAMP_S, TYPE_TIMESTAMP_MS, TYPE_TIMESTAMP_NS, TYPE_TIMESTAMP_TZ, TYPE_TIME:
+		return TYPE_BIGINT
+	case TYPE_DATE:
+		return TYPE_INTEGER
+	}
+	return vec.Type
+}
+
+// width returns the size of a value in the vector's data.
+// Nested types return the size of their entry, and not of their children.
+func (vec *vector) width() int {
+	switch vec.physicalType() {
+	case TYPE_BOOLEAN, TYPE_TINYINT, TYPE_UTINYINT:
+		return 1
+	case TYPE_SMALLINT, TYPE_USMALLINT:
+		return 2
+	case TYPE_INTEGER, TYPE_UINTEGER, TYPE_FLOAT:
+		return 4
+	case TYPE_BIGINT, TYPE_UBIGINT, TYPE_DOUBLE, TYPE_TIME_TZ:
+		return 8
+	}
+	return 16
+}
+
+func fixedWidthTypeOf(v any) Type {
+	switch v.(type) {
+	case bool:
+		return TYPE_BOOLEAN
+	case int8:
+		return TYPE_TINYINT
+	case int16:
+		return TYPE_SMALLINT
+	case int32:
+		return TYPE_INTEGER
+	case int64:
+		return TYPE_BIGINT
+	case uint8:
+		return TYPE_UTINYINT
+	case uint16:
+		return TYPE_USMALLINT
+	case uint32:
+		return TYPE_UINTEGER
+	case uint64:
+		return TYPE_UBIGINT
+	case float32:
+		return TYPE_FLOAT
+	case float64:
+		return TYPE_DOUBLE
+	}
+	return TYPE_INVALID
+}
@@ -0,0 +1,351 @@
+package duckdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/marcboeker/go-duckdb/mapping"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetColumn(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	ctx := context.Background()
+	conn := openConnWrapper(t, db, ctx)
+	defer closeConnWrapper(t, conn)
+
+	query := `SELECT
+		i,
+		i::INTEGER AS i32,
+		i::DOUBLE / 2 AS d,
+		i % 2 = 0 AS b,
+		CASE WHEN i % 3 = 0 THEN NULL ELSE (i % 256)::UTINYINT END AS u8,
+		(i / 100)::DECIMAL(9, 2) AS dec,
+		DATE '1970-01-01' + i::INTEGER AS date,
+		make_timestamp(i) AS ts
+	FROM range(3000) t(i)`
+
+	rowIdx := 0
+	for chunk, err := range QueryChunks(ctx, conn, query) {
+		require.NoError(t, err)
+
+		ints, validity, err := GetColumn[int64](*chunk, 0)
+		require.NoError(t, err)
+		require.True(t, validity.AllValid())
+		require.Len(t, ints, chunk.GetSize())
+
+		i32s, _, err := GetColumn[int32](*chunk, 1)
+		require.NoError(t, err)
+		doubles, _, err := GetColumn[float64](*chunk, 2)
+		require.NoError(t, err)
+		bools, _, err := GetColumn[bool](*chunk, 3)
+		require.NoError(t, err)
+		u8s, u8Validity, err := GetColumn[uint8](*chunk, 4)
+		require.NoError(t, err)
+		require.False(t, u8Validity.AllValid())
+		decimals, _, err := GetColumn[int32](*chunk, 5)
+		require.NoError(t, err)
+		dates, _, err := GetColumn[int32](*chunk, 6)
+		require.NoError(t, err)
+		timestamps, _, err := GetColumn[int64](*chunk, 7)
+		require.NoError(t, err)
+
+		for j := range ints {
+			i := int64(rowIdx)
+			require.Equal(t, i, ints[j])
+			require.Equal(t, int32(i), i32s[j])
+			require.Equal(t, float64(i)/2, doubles[j])
+			require.Equal(t, i%2 == 0, bools[j])
+			require.Equal(t, i%3 != 0, u8Validity.IsValid(j))
+			if u8Validity.IsValid(j) {
+				require.Equal(t, uint8(i), u8s[j])
+			}
+			require.Equal(t, int32(i), decimals[j])
+			require.Equal(t, int32(i), dates[j])
+			require.Equal(t, i, timestamps[j])
+			rowIdx++
+		}
+	}
+	require.Equal(t, 3000, rowIdx)
+}
+
+func TestGetStringColumn(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	ctx := context.Background()
+	conn := openConnWrapper(t, db, ctx)
+	defer closeConnWrapper(t, conn)
+
+	query := `SELECT
+		CASE WHEN i = 2 THEN NULL ELSE repeat('x', i::INTEGER * 5) END AS s,
+		repeat('y', i::INTEGER * 5)::BLOB AS b
+	FROM range(5) t(i)`
+
+	for chunk, err := range QueryChunks(ctx, conn, query) {
+		require.NoError(t, err)
+
+		strs, validity, err := GetStringColumn(*chunk, 0)
+		require.NoError(t, err)
+		require.Equal(t, 5, strs.Len())
+		blobs, blobValidity, err := GetStringColumn(*chunk, 1)
+		require.NoError(t, err)
+		require.True(t, blobValidity.AllValid())
+
+		for i := 0; i < strs.Len(); i++ {
+			// Strings with up to 12 bytes are inlined.
+			require.Equal(t, i != 2, validity.IsValid(i))
+			if validity.IsValid(i) {
+				require.Equal(t, strings.Repeat("x", i*5), strs.String(i))
+			}
+			require.Equal(t, []byte(strings.Repeat("y", i*5)), append([]byte{}, blobs.Bytes(i)...))
+		}
+	}
+}
+
+func TestGetColumnErrors(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	ctx := context.Background()
+	conn := openConnWrapper(t, db, ctx)
+	defer closeConnWrapper(t, conn)
+
+	for chunk, err := range QueryChunks(ctx, conn, `SELECT 42::INTEGER, 'x', 1.5::DECIMAL(38, 1)`) {
+		require.NoError(t, err)
+
+		_, _, err = GetColumn[int64](*chunk, 0)
+		testError(t, err, errAPI.Error(), castErrMsg)
+		_, _, err = GetColumn[int32](*chunk, 1)
+		testError(t, err, errAPI.Error(), castErrMsg)
+		_, _, err = GetColumn[int64](*chunk, 2)
+		testError(t, err, errAPI.Error(), castErrMsg)
+		_, _, err = GetColumn[int32](*chunk, 3)
+		testError(t, err, errAPI.Error(), columnCountErrMsg)
+		_, _, err = GetStringColumn(*chunk, 0)
+		testError(t, err, errAPI.Error(), castErrMsg)
+	}
+}
+
+type setColumnTableUDF struct {
+	n     int64
+	count int64
+}
+
+func bindSetColumnTableUDF(namedArgs map[string]any, args ...any) (ChunkTableSource, error) {
+	return &setColumnTableUDF{n: args[0].(int64)}, nil
+}
+
+func (udf *setColumnTableUDF) ColumnInfos() []ColumnInfo {
+	bigintInfo, _ := NewTypeInfo(TYPE_BIGINT)
+	varcharInfo, _ := NewTypeInfo(TYPE_VARCHAR)
+	blobInfo, _ := NewTypeInfo(TYPE_BLOB)
+	return []ColumnInfo{{Name: "i", T: bigintInfo}, {Name: "s", T: varcharInfo}, {Name: "b", T: blobInfo}}
+}
+
+func (udf *setColumnTableUDF) Init() {}
+
+func (udf *setColumnTableUDF) Cardinality() *CardinalityInfo {
+	return nil
+}
+
+func (udf *setColumnTableUDF) FillChunk(chunk DataChunk) error {
+	size := int(min(udf.n-udf.count, int64(GetDataChunkCapacity())))
+	ints := make([]int64, size)
+	strs := make([]string, size)
+	blobs := make([][]byte, size)
+	nulls := NewValidity(size)
+	for i := range size {
+		v := udf.count + int64(i)
+		ints[i] = v
+		strs[i] = strings.Repeat("s", int(v%20))
+		blobs[i] = []byte{byte(v)}
+		if v%7 == 0 {
+			nulls.SetNull(i)
+		}
+	}
+	udf.count += int64(size)
+
+	if err := SetColumn(chunk, 0, ints, Validity{}); err != nil {
+		return err
+	}
+	if err := SetColumn(chunk, 1, strs, nulls); err != nil {
+		return err
+	}
+	if err := SetColumn(chunk, 2, blobs, Validity{}); err != nil {
+		return err
+	}
+	return chunk.SetSize(size)
+}
+
+func TestSetColumn(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	ctx := context.Background()
+	conn := openConnWrapper(t, db, ctx)
+	defer closeConnWrapper(t, conn)
+
+	bigintInfo, err := NewTypeInfo(TYPE_BIGINT)
+	require.NoError(t, err)
+	err = RegisterTableUDF(conn, "set_column", ChunkTableFunction{
+		Config:        TableFunctionConfig{Arguments: []TypeInfo{bigintInfo}},
+		BindArguments: bindSetColumnTableUDF,
+	})
+	require.NoError(t, err)
+
+	var count, sum, nullCount, longCount int64
+	err = conn.QueryRowContext(ctx, `SELECT count(*), sum(i), count(*) FILTER (s IS NULL), count(*) FILTER (length(s) > 12)
+		FROM set_column(5000) WHERE b = unhex(printf('%02X', i % 256))`).Scan(&count, &sum, &nullCount, &longCount)
+	require.NoError(t, err)
+	require.Equal(t, int64(5000), count)
+	require.Equal(t, int64(4999*5000/2), sum)
+	require.Equal(t, int64(715), nullCount)
+	require.Positive(t, longCount)
+
+	var s string
+	err = conn.QueryRowContext(ctx, `SELECT s FROM set_column(20) WHERE i = 19`).Scan(&s)
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat("s", 19), s)
+}
+
+// projectionTableUDF writes the column a with SetChunkValue, b with SetColumn, and c with SetValue.
+type projectionTableUDF struct {
+	done bool
+}
+
+func bindProjectionTableUDF(map[string]any, ...any) (ChunkTableSource, error) {
+	return &projectionTableUDF{}, nil
+}
+
+func (udf *projectionTableUDF) ColumnInfos() []ColumnInfo {
+	info, _ := NewTypeInfo(TYPE_BIGINT)
+	return []ColumnInfo{{Name: "a", T: info}, {Name: "b", T: info}, {Name: "c", T: info}}
+}
+
+func (udf *projectionTableUDF) Init() {}
+
+func (udf *projectionTableUDF) Cardinality() *CardinalityInfo {
+	return nil
+}
+
+func (udf *projectionTableUDF) FillChunk(chunk DataChunk) error {
+	if udf.done {
+		return chunk.SetSize(0)
+	}
+	udf.done = true
+
+	// All getters and setters take the index of the table function's column.
+	for i := range 3 {
+		if err := SetChunkValue(chunk, 0, i, int64(i)); err != nil {
+			return err
+		}
+		if err := chunk.SetValue(2, i, int64(100+i)); err != nil {
+			return err
+		}
+	}
+	if err := SetColumn(chunk, 1, []int64{10, 11, 12}, Validity{}); err != nil {
+		return err
+	}
+	if err := chunk.SetSize(3); err != nil {
+		return err
+	}
+
+	b, _, err := GetColumn[int64](chunk, 1)
+	if err != nil {
+		return err
+	}
+	c, err := chunk.GetValue(2, 2)
+	if err != nil {
+		return err
+	}
+	if chunk.IsProjected(1) != (len(b) == 3 && b[2] == 12) {
+		return fmt.Errorf("unexpected column b: %v", b)
+	}
+	if chunk.IsProjected(2) != (c == int64(102)) {
+		return fmt.Errorf("unexpected value of column c: %v", c)
+	}
+	return nil
+}
+
+func TestDataChunkProjection(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	ctx := context.Background()
+	conn := openConnWrapper(t, db, ctx)
+	defer closeConnWrapper(t, conn)
+
+	err := RegisterTableUDF(conn, "projection", ChunkTableFunction{
+		BindArguments: bindProjectionTableUDF,
+	})
+	require.NoError(t, err)
+
+	for query, expected := range map[string]string{
+		`SELECT string_agg(b::VARCHAR, ',') FROM projection()`:                       "10,11,12",
+		`SELECT string_agg(c::VARCHAR, ',') FROM projection()`:                       "100,101,102",
+		`SELECT string_agg(c || ':' || a, ',') FROM projection()`:                    "100:0,101:1,102:2",
+		`SELECT string_agg(b || ':' || a || ':' || c, ',') FROM projection()`:        "10:0:100,11:1:101,12:2:102",
+		`SELECT string_agg(concat_ws(':', a, b, c), ',') FROM projection()`:          "0:10:100,1:11:101,2:12:102",
+		`SELECT string_agg(concat_ws(':', c, b), ',') FROM projection() WHERE a > 0`: "101:11,102:12",
+	} {
+		var actual string
+		require.NoError(t, conn.QueryRowContext(ctx, query).Scan(&actual), query)
+		require.Equal(t, expected, actual, query)
+	}
+}
+
+func TestValidity(t *testing.T) {
+	var v Validity
+	require.True(t, v.AllValid())
+	require.True(t, v.IsValid(100))
+	require.Panics(t, func() { v.SetNull(0) })
+
+	v = NewValidity(130)
+	require.False(t, v.AllValid())
+	v.SetNull(0)
+	v.SetNull(64)
+	v.SetNull(129)
+	for i := range 130 {
+		require.Equal(t, i != 0 && i != 64 && i != 129, v.IsValid(i))
+	}
+	v.SetValid(64)
+	require.True(t, v.IsValid(64))
+}
+
+func TestSetColumnErrors(t *testing.T) {
+	var chunk DataChunk
+	types := []TypeInfo{}
+	for _, typ := range []Type{TYPE_INTEGER, TYPE_VARCHAR} {
+		info, err := NewTypeInfo(typ)
+		require.NoError(t, err)
+		types = append(types, info)
+	}
+	logicalTypes := make([]mapping.LogicalType, len(types))
+	for i, info := range types {
+		logicalTypes[i] = info.logicalType()
+	}
+	require.NoError(t, chunk.initFromTypes(logicalTypes, true))
+	defer chunk.close()
+	for i := range logicalTypes {
+		mapping.DestroyLogicalType(&logicalTypes[i])
+	}
+
+	require.NoError(t, SetColumn(chunk, 0, []int32{1, 2, 3}, Validity{}))
+	err := SetColumn(chunk, 0, []int64{1}, Validity{})
+	testError(t, err, errAPI.Error(), castErrMsg)
+	err = SetColumn(chunk, 0, []string{"x"}, Validity{})
+	testError(t, err, errAPI.Error(), castErrMsg)
+	err = SetColumn(chunk, 1, []int32{1}, Validity{})
+	testError(t, err, errAPI.Error(), castErrMsg)
+	err = SetColumn(chunk, 2, []int32{1}, Validity{})
+	testError(t, err, errAPI.Error(), columnCountErrMsg)
+	err = SetColumn(chunk, 0, make([]int32, GetDataChunkCapacity()+1), Validity{})
+	testError(t, err, errAPI.Error(), errVectorSize.Error())
+	err = SetColumn(chunk, 0, []int32{1, 2}, NewValidity(1))
+	testError(t, err, errAPI.Error(), invalidInputErrMsg)
+}
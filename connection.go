@@ -5,7 +5,10 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"io"
 	"math/big"
+	"reflect"
+	"time"
 
 	"github.com/marcboeker/go-duckdb/mapping"
 )
@@ -16,13 +19,38 @@ type Conn struct {
 	conn   mapping.Connection
 	closed bool
 	tx     bool
+
+	// tzLocation is the location of scanned TIMESTAMPTZ values. If nil, they are in UTC.
+	tzLocation *time.Location
+	// tzSession is true, if scanned TIMESTAMPTZ values are in the location of the TimeZone setting.
+	tzSession bool
+	// sessionLocation caches the location of the TimeZone setting.
+	// Executing a SET statement invalidates it.
+	sessionLocation *time.Location
+	// orderedMaps is true, if scanned MAP values are always an OrderedMap.
+	orderedMaps bool
 }
 
 // CheckNamedValue implements the driver.NamedValueChecker interface.
+// It binds a *Decimal like its Decimal, or as NULL, if nil.
 func (conn *Conn) CheckNamedValue(nv *driver.NamedValue) error {
-	switch nv.Value.(type) {
-	case *big.Int, Interval, []any, []bool, []int8, []int16, []int32, []int64, []uint8, []uint16,
-		[]uint32, []uint64, []float32, []float64, []string, map[string]any:
+	switch v := nv.Value.(type) {
+	case *Decimal:
+		if v == nil {
+			nv.Value = nil
+		} else {
+			nv.Value = *v
+		}
+		return nil
+	case *big.Int, Decimal, Interval, []any, []bool, []int8, []int16, []int32, []int64, []uint8, []uint16,
+		[]uint32, []uint64, []float32, []float64, []string, map[string]any, Map, OrderedMap:
+		return nil
+	case driver.Valuer:
+		return driver.ErrSkip
+	}
+
+	// Bind other maps, e.g., a TypedMap, as a MAP.
+	if nv.Value != nil && reflect.TypeOf(nv.Value).Kind() == reflect.Map {
 		return nil
 	}
 	return driver.ErrSkip
@@ -140,6 +168,38 @@ func (conn *Conn) Close() error {
 	return nil
 }
 
+// timestampTZLocation returns the location of scanned TIMESTAMPTZ values, or nil, if they are in UTC.
+func (conn *Conn) timestampTZLocation() (*time.Location, error) {
+	if !conn.tzSession {
+		return conn.tzLocation, nil
+	}
+	if conn.sessionLocation != nil {
+		return conn.sessionLocation, nil
+	}
+
+	r, err := conn.QueryContext(context.Background(), `SELECT current_setting('TimeZone')`, nil)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]driver.Value, 1)
+	err = r.Next(values)
+	errClose := r.Close()
+	if err == io.EOF {
+		err = errors.New("missing TimeZone setting")
+	}
+	if err != nil || errClose != nil {
+		return nil, errors.Join(err, errClose)
+	}
+
+	name, _ := values[0].(string)
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	conn.sessionLocation = loc
+	return loc, nil
+}
+
 func (conn *Conn) extractStmts(query string) (*mapping.ExtractedStatements, mapping.IdxT, error) {
 	var stmts mapping.ExtractedStatements
 
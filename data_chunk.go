@@ -2,6 +2,8 @@ package duckdb
 
 import "C"
 import (
+	"time"
+
 	"github.com/marcboeker/go-duckdb/mapping"
 )
 
@@ -15,6 +17,56 @@ type DataChunk struct {
 	columnNames []string
 	// size caches the size after initialization.
 	size int
+	// projection maps the columns of a table UDF to the columns of its output chunk, if not nil.
+	// Non-projected columns map to -1.
+	projection []int
+	// owned is true for chunks created by NewDataChunk.
+	owned bool
+}
+
+// NewDataChunk returns a writable data chunk with columns of the given types, e.g., to append with
+// Appender.AppendChunk. Its size is zero, see SetSize. The chunk must be closed with Close.
+func NewDataChunk(types []TypeInfo) (*DataChunk, error) {
+	if len(types) == 0 {
+		return nil, getError(errAPI, columnCountError(0, 1))
+	}
+
+	logicalTypes := make([]mapping.LogicalType, 0, len(types))
+	defer destroyLogicalTypes(&logicalTypes)
+	for i, info := range types {
+		if info == nil {
+			return nil, getError(errAPI, addIndexToError(interfaceIsNilError("TypeInfo"), i))
+		}
+		logicalTypes = append(logicalTypes, info.logicalType())
+	}
+
+	chunk := &DataChunk{owned: true}
+	if err := chunk.initFromTypes(logicalTypes, true); err != nil {
+		return nil, getError(errAPI, err)
+	}
+	chunk.setEmpty()
+	return chunk, nil
+}
+
+// Reset sets the size of a chunk created by NewDataChunk to zero, and makes all its rows valid,
+// so that it can be filled again.
+func (chunk *DataChunk) Reset() {
+	if !chunk.owned {
+		return
+	}
+	chunk.reset(true)
+	chunk.setEmpty()
+}
+
+// Close destroys a chunk created by NewDataChunk. Chunks passed to callbacks or returned by
+// QueryChunks are owned by go-duckdb, and Close does nothing for them.
+func (chunk *DataChunk) Close() {
+	if !chunk.owned {
+		return
+	}
+	chunk.close()
+	chunk.owned = false
+	chunk.columns = nil
 }
 
 // GetDataChunkCapacity returns the capacity of a data chunk.
@@ -37,37 +89,86 @@ func (chunk *DataChunk) SetSize(size int) error {
 	return nil
 }
 
-// GetValue returns a single value of a column.
+// IsProjected returns whether the column is projected. In the output chunk of a table UDF,
+// the column indexes of the chunk's getters and setters are the indexes of the table function's columns.
+// They map to the projected columns of the chunk, and the columns that are not projected are skipped:
+// setting their values does nothing, and getting their values returns nil.
+// Outside of table UDFs, all columns are projected.
+func (chunk *DataChunk) IsProjected(colIdx int) bool {
+	if chunk.projection == nil {
+		return colIdx >= 0 && colIdx < len(chunk.columns)
+	}
+	return colIdx >= 0 && colIdx < len(chunk.projection) && chunk.projection[colIdx] >= 0
+}
+
+// GetValue returns a single value of a column, see IsProjected.
 func (chunk *DataChunk) GetValue(colIdx int, rowIdx int) (any, error) {
-	if colIdx >= len(chunk.columns) {
-		return nil, getError(errAPI, columnCountError(colIdx, len(chunk.columns)))
+	column, err := chunk.projectedColumn(colIdx)
+	if column == nil {
+		return nil, err
 	}
-	column := &chunk.columns[colIdx]
 
 	return column.getFn(column, mapping.IdxT(rowIdx)), nil
 }
 
-// SetValue writes a single value to a column in a data chunk.
+// SetValue writes a single value to a column in a data chunk, see IsProjected.
 // Note that this requires casting the type for each invocation.
 // NOTE: Custom ENUM types must be passed as string.
 func (chunk *DataChunk) SetValue(colIdx int, rowIdx int, val any) error {
-	if colIdx >= len(chunk.columns) {
-		return getError(errAPI, columnCountError(colIdx, len(chunk.columns)))
+	column, err := chunk.projectedColumn(colIdx)
+	if column == nil {
+		return err
 	}
-	column := &chunk.columns[colIdx]
 
 	return column.setFn(column, mapping.IdxT(rowIdx), val)
 }
 
-// SetChunkValue writes a single value to a column in a data chunk.
+// SetChunkValue writes a single value to a column in a data chunk, see IsProjected.
 // The difference with `chunk.SetValue` is that `SetChunkValue` does not
 // require casting the value to `any` (implicitly).
 // NOTE: Custom ENUM types must be passed as string.
 func SetChunkValue[T any](chunk DataChunk, colIdx int, rowIdx int, val T) error {
-	if colIdx >= len(chunk.columns) {
-		return getError(errAPI, columnCountError(colIdx, len(chunk.columns)))
+	column, err := chunk.projectedColumn(colIdx)
+	if column == nil {
+		return err
+	}
+	return setVectorVal(column, mapping.IdxT(rowIdx), val)
+}
+
+// projectedColumn returns the vector of a column. In a table UDF, it maps the index of the table function's column
+// to the column of its output chunk. It returns nil without an error for columns that are not projected.
+func (chunk *DataChunk) projectedColumn(colIdx int) (*vector, error) {
+	if chunk.projection == nil {
+		return chunk.column(colIdx)
+	}
+	if colIdx < 0 || colIdx >= len(chunk.projection) {
+		return nil, getError(errAPI, columnCountError(colIdx, len(chunk.projection)))
+	}
+	if chunk.projection[colIdx] < 0 {
+		return nil, nil
+	}
+	return chunk.column(chunk.projection[colIdx])
+}
+
+func (chunk *DataChunk) hasTimestampTZ() bool {
+	for i := range chunk.columns {
+		if chunk.columns[i].hasTimestampTZ() {
+			return true
+		}
+	}
+	return false
+}
+
+func (chunk *DataChunk) setTimestampTZLocation(loc *time.Location) {
+	for i := range chunk.columns {
+		chunk.columns[i].setTimestampTZLocation(loc)
+	}
+}
+
+func (chunk *DataChunk) setOrderedMaps() {
+	for i := range chunk.columns {
+		chunk.columns[i].setOrderedMaps()
 	}
-	return setVectorVal(&chunk.columns[colIdx], mapping.IdxT(rowIdx), val)
 }
 
 func (chunk *DataChunk) initFromTypes(types []mapping.LogicalType, writable bool) error {
@@ -92,6 +193,11 @@ func (chunk *DataChunk) initFromTypes(types []mapping.LogicalType, writable bool
 	return nil
 }
 
+func (chunk *DataChunk) setEmpty() {
+	mapping.DataChunkSetSize(chunk.chunk, 0)
+	chunk.size = 0
+}
+
 func (chunk *DataChunk) reset(writable bool) {
 	mapping.DataChunkReset(chunk.chunk)
 	chunk.initVectors(writable)
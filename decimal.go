@@ -0,0 +1,360 @@
+package duckdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const max_decimal_width = 38
+
+// Decimal is a fixed-point number with Width significant digits, of which Scale digits
+// are after the decimal point. Its numeric value is Value / 10^Scale.
+// A nil Value is treated as zero.
+// Decimal cannot implement driver.Valuer, as the method would clash with its Value field.
+// Instead, the driver binds Decimal and *Decimal parameters directly, see Conn.CheckNamedValue.
+type Decimal struct {
+	Width uint8
+	Scale uint8
+	Value *big.Int
+}
+
+// ParseDecimal parses a decimal number, e.g., "-123.45" or "1.2345e2".
+// The resulting width and scale are the smallest ones that represent the number exactly.
+func ParseDecimal(s string) (Decimal, error) {
+	value, scale, ok := parseDecimal(s)
+	if !ok {
+		return Decimal{}, getError(errDecimalSyntax, fmt.Errorf("%q", s))
+	}
+
+	switch {
+	case value.Sign() == 0:
+		scale = min(max(scale, 0), max_decimal_width)
+	case scale < 0:
+		if -scale > max_decimal_width {
+			return Decimal{}, getError(errDecimalOverflow, fmt.Errorf("%q", s))
+		}
+		value = rescaleDecimalValue(value, scale, 0)
+		scale = 0
+	}
+
+	// Trailing zeros after the decimal point do not change the value.
+	ten := big.NewInt(10)
+	for scale > max_decimal_width && new(big.Int).Rem(value, ten).Sign() == 0 {
+		value.Quo(value, ten)
+		scale--
+	}
+
+	width := max(decimalDigits(value), scale)
+	if width > max_decimal_width {
+		return Decimal{}, getError(errDecimalOverflow, fmt.Errorf("%q", s))
+	}
+	return Decimal{Width: uint8(width), Scale: uint8(scale), Value: value}, nil
+}
+
+// NewDecimalFromFloat converts f to a DECIMAL(width, scale).
+// It rounds the shortest decimal representation of f half away from zero.
+func NewDecimalFromFloat(f float64, width uint8, scale uint8) (Decimal, error) {
+	if err := validateDecimal(width, scale); err != nil {
+		return Decimal{}, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, getError(errDecimalNonFinite, fmt.Errorf("%v", f))
+	}
+
+	value, fromScale, _ := parseDecimal(strconv.FormatFloat(f, 'e', -1, 64))
+	value = rescaleDecimalValue(value, fromScale, int(scale))
+	if decimalDigits(value) > int(width) {
+		return Decimal{}, getError(errDecimalOverflow, fmt.Errorf("%v does not fit DECIMAL(%d,%d)", f, width, scale))
+	}
+	return Decimal{Width: width, Scale: scale, Value: value}, nil
+}
+
+func (d Decimal) Float64() float64 {
+	scale := big.NewInt(int64(d.Scale))
+	factor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), scale, nil))
+	value := new(big.Float).SetInt(d.value())
+	value.Quo(value, factor)
+	f, _ := value.Float64()
+	return f
+}
+
+func (d Decimal) String() string {
+	// Get the sign, and return early, if zero.
+	if d.value().Sign() == 0 {
+		return "0"
+	}
+
+	// Remove the sign from the string integer value
+	var signStr string
+	scaleless := d.Value.String()
+	if d.Value.Sign() < 0 {
+		signStr = "-"
+		scaleless = scaleless[1:]
+	}
+
+	// Remove all zeros from the right side
+	zeroTrimmed := strings.TrimRightFunc(scaleless, func(r rune) bool { return r == '0' })
+	scale := int(d.Scale) - (len(scaleless) - len(zeroTrimmed))
+
+	// If the string is still bigger than the scale factor, output it without a decimal point
+	if scale <= 0 {
+		return signStr + zeroTrimmed + strings.Repeat("0", -1*scale)
+	}
+
+	// Pad a number with 0.0's if needed
+	if len(zeroTrimmed) <= scale {
+		return fmt.Sprintf("%s0.%s%s", signStr, strings.Repeat("0", scale-len(zeroTrimmed)), zeroTrimmed)
+	}
+	return signStr + zeroTrimmed[:len(zeroTrimmed)-scale] + "." + zeroTrimmed[len(zeroTrimmed)-scale:]
+}
+
+// Rat returns the exact value of d.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.value(), decimalFactor(int(d.Scale)))
+}
+
+// Rescale converts d to a DECIMAL(width, scale).
+// Reducing the scale rounds half away from zero.
+func (d Decimal) Rescale(width uint8, scale uint8) (Decimal, error) {
+	if err := validateDecimal(width, scale); err != nil {
+		return Decimal{}, err
+	}
+	value := rescaleDecimalValue(d.value(), int(d.Scale), int(scale))
+	if decimalDigits(value) > int(width) {
+		return Decimal{}, getError(errDecimalOverflow, fmt.Errorf("%s does not fit DECIMAL(%d,%d)", d.String(), width, scale))
+	}
+	return Decimal{Width: width, Scale: scale, Value: value}, nil
+}
+
+// Cmp compares d and other, and returns -1 if d < other, 0 if d == other, and +1 if d > other.
+// The comparison is independent of the width and scale.
+func (d Decimal) Cmp(other Decimal) int {
+	scale := int(max(d.Scale, other.Scale))
+	x := rescaleDecimalValue(d.value(), int(d.Scale), scale)
+	y := rescaleDecimalValue(other.value(), int(other.Scale), scale)
+	return x.Cmp(y)
+}
+
+// Add returns d + other.
+// Like in DuckDB, the result scale is the larger of both scales,
+// and the result width has room for one additional integer digit, up to a width of 38.
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	return d.addSub(other, false)
+}
+
+// Sub returns d - other. The result width and scale follow the rules of Add.
+func (d Decimal) Sub(other Decimal) (Decimal, error) {
+	return d.addSub(other, true)
+}
+
+// Mul returns d * other.
+// Like in DuckDB, the result scale is the sum of both scales,
+// and the result width is the sum of both widths, up to a width of 38.
+func (d Decimal) Mul(other Decimal) (Decimal, error) {
+	scale := int(d.Scale) + int(other.Scale)
+	if scale > max_decimal_width {
+		return Decimal{}, getError(errDecimalOverflow, fmt.Errorf("the result scale %d exceeds %d", scale, max_decimal_width))
+	}
+	width := min(int(d.Width)+int(other.Width), max_decimal_width)
+	value := new(big.Int).Mul(d.value(), other.value())
+	return newDecimalResult(value, width, scale)
+}
+
+func (d Decimal) addSub(other Decimal, sub bool) (Decimal, error) {
+	scale := int(max(d.Scale, other.Scale))
+	intDigits := max(int(d.Width)-int(d.Scale), int(other.Width)-int(other.Scale))
+	width := min(intDigits+scale+1, max_decimal_width)
+
+	x := rescaleDecimalValue(d.value(), int(d.Scale), scale)
+	y := rescaleDecimalValue(other.value(), int(other.Scale), scale)
+	if sub {
+		return newDecimalResult(x.Sub(x, y), width, scale)
+	}
+	return newDecimalResult(x.Add(x, y), width, scale)
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts DECIMAL, integer, HUGEINT, floating-point, and string values.
+func (d *Decimal) Scan(v any) error {
+	var err error
+	switch val := v.(type) {
+	case Decimal:
+		*d = val
+	case string:
+		*d, err = ParseDecimal(val)
+	case []byte:
+		*d, err = ParseDecimal(string(val))
+	case float32:
+		return d.scanFloat(float64(val))
+	case float64:
+		return d.scanFloat(val)
+	case *big.Int:
+		if val == nil {
+			return fmt.Errorf("invalid type `%T` for scanning `Decimal`", v)
+		}
+		*d, err = ParseDecimal(val.String())
+	default:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			*d, err = ParseDecimal(strconv.FormatInt(rv.Int(), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			*d, err = ParseDecimal(strconv.FormatUint(rv.Uint(), 10))
+		default:
+			return fmt.Errorf("invalid type `%T` for scanning `Decimal`", v)
+		}
+	}
+	return err
+}
+
+func (d *Decimal) scanFloat(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return getError(errDecimalNonFinite, fmt.Errorf("%v", f))
+	}
+	var err error
+	*d, err = ParseDecimal(strconv.FormatFloat(f, 'e', -1, 64))
+	return err
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It encodes d as a JSON number to avoid any loss of precision. Unlike String, it keeps the trailing zeros
+// of the scale, e.g., 1.50 for a DECIMAL(3,2), so that UnmarshalJSON restores the scale.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.scaledString()), nil
+}
+
+// scaledString returns the string representation of d with exactly Scale digits after the decimal point.
+func (d Decimal) scaledString() string {
+	value := d.value()
+	if d.Scale == 0 {
+		return value.String()
+	}
+
+	digits := new(big.Int).Abs(value).String()
+	var sign string
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	if pad := int(d.Scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.Scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts JSON numbers and strings. A JSON null leaves d unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	*d, err = ParseDecimal(s)
+	return err
+}
+
+func (d Decimal) value() *big.Int {
+	if d.Value == nil {
+		return new(big.Int)
+	}
+	return d.Value
+}
+
+func validateDecimal(width uint8, scale uint8) error {
+	if width < 1 || width > max_decimal_width {
+		return getError(errAPI, errInvalidDecimalWidth)
+	}
+	if scale > width {
+		return getError(errAPI, errInvalidDecimalScale)
+	}
+	return nil
+}
+
+func newDecimalResult(value *big.Int, width int, scale int) (Decimal, error) {
+	if decimalDigits(value) > width {
+		return Decimal{}, getError(errDecimalOverflow, fmt.Errorf("the result does not fit DECIMAL(%d,%d)", width, scale))
+	}
+	return Decimal{Width: uint8(width), Scale: uint8(scale), Value: value}, nil
+}
+
+// parseDecimal returns the unscaled value and the scale of s.
+// The scale can be negative or exceed max_decimal_width.
+func parseDecimal(s string) (*big.Int, int, bool) {
+	s = strings.TrimSpace(s)
+
+	exp := 0
+	if idx := strings.IndexAny(s, "eE"); idx != -1 {
+		var err error
+		if exp, err = strconv.Atoi(s[idx+1:]); err != nil {
+			return nil, 0, false
+		}
+		s = s[:idx]
+	}
+
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.ContainsFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) {
+		return nil, 0, false
+	}
+
+	// Guard against an exponent that overflows the scale.
+	if exp < -math.MaxInt32 || exp > math.MaxInt32 {
+		return nil, 0, false
+	}
+
+	value, _ := new(big.Int).SetString(digits, 10)
+	if neg {
+		value.Neg(value)
+	}
+	return value, len(fracPart) - exp, true
+}
+
+// rescaleDecimalValue converts an unscaled value from one scale to another.
+// Reducing the scale rounds half away from zero.
+func rescaleDecimalValue(value *big.Int, from int, to int) *big.Int {
+	if to >= from {
+		return new(big.Int).Mul(value, decimalFactor(to-from))
+	}
+
+	factor := decimalFactor(from - to)
+	q, r := new(big.Int).QuoRem(value, factor, new(big.Int))
+	if r.Abs(r).Lsh(r, 1).Cmp(factor) >= 0 {
+		if value.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+func decimalFactor(scale int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+}
+
+// decimalDigits returns the number of digits of the unscaled value, ignoring its sign.
+func decimalDigits(value *big.Int) int {
+	if value.Sign() == 0 {
+		return 1
+	}
+	return len(value.Text(10)) - max(0, -value.Sign())
+}
@@ -0,0 +1,521 @@
+package duckdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimal(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	t.Run("SELECT all possible DECIMAL widths", func(t *testing.T) {
+		for i := 1; i <= 38; i++ {
+			r := db.QueryRow(fmt.Sprintf(`SELECT 0::DECIMAL(%d, 1)`, i))
+			var actual Decimal
+			require.NoError(t, r.Scan(&actual))
+			expected := Decimal{Width: uint8(i), Value: big.NewInt(0), Scale: 1}
+			require.Equal(t, expected, actual)
+		}
+	})
+
+	t.Run("SELECT different DECIMAL types", func(t *testing.T) {
+		res, err := db.Query(`SELECT * FROM (VALUES
+			(1.23::DECIMAL(3, 2)),
+			(-1.23::DECIMAL(3, 2)),
+			(123.45::DECIMAL(5, 2)),
+			(-123.45::DECIMAL(5, 2)),
+			(123456789.01::DECIMAL(11, 2)),
+			(-123456789.01::DECIMAL(11, 2)),
+			(1234567890123456789.234::DECIMAL(22, 3)),
+			(-1234567890123456789.234::DECIMAL(22, 3)),
+		) v
+		ORDER BY v ASC`)
+		require.NoError(t, err)
+		defer closeRowsWrapper(t, res)
+
+		bigNumber, success := new(big.Int).SetString("1234567890123456789234", 10)
+		require.True(t, success)
+		bigNegativeNumber, success := new(big.Int).SetString("-1234567890123456789234", 10)
+		require.True(t, success)
+		tests := []struct {
+			input string
+			want  Decimal
+		}{
+			{input: "1.23::DECIMAL(3, 2)", want: Decimal{Value: big.NewInt(123), Width: 3, Scale: 2}},
+			{input: "-1.23::DECIMAL(3, 2)", want: Decimal{Value: big.NewInt(-123), Width: 3, Scale: 2}},
+			{input: "123.45::DECIMAL(5, 2)", want: Decimal{Value: big.NewInt(12345), Width: 5, Scale: 2}},
+			{input: "-123.45::DECIMAL(5, 2)", want: Decimal{Value: big.NewInt(-12345), Width: 5, Scale: 2}},
+			{input: "123456789.01::DECIMAL(11, 2)", want: Decimal{Value: big.NewInt(12345678901), Width: 11, Scale: 2}},
+			{input: "-123456789.01::DECIMAL(11, 2)", want: Decimal{Value: big.NewInt(-12345678901), Width: 11, Scale: 2}},
+			{input: "1234567890123456789.234::DECIMAL(22, 3)", want: Decimal{Value: bigNumber, Width: 22, Scale: 3}},
+			{input: "-1234567890123456789.234::DECIMAL(22, 3)", want: Decimal{Value: bigNegativeNumber, Width: 22, Scale: 3}},
+		}
+		for _, test := range tests {
+			r := db.QueryRow(fmt.Sprintf(`SELECT %s`, test.input))
+			var fs Decimal
+			require.NoError(t, r.Scan(&fs))
+			compareDecimal(t, test.want, fs)
+		}
+	})
+
+	t.Run("SELECT a huge DECIMAL ", func(t *testing.T) {
+		bigInt, success := new(big.Int).SetString("12345678901234567890123456789", 10)
+		require.True(t, success)
+		var f Decimal
+		require.NoError(t, db.QueryRow("SELECT 123456789.01234567890123456789::DECIMAL(29, 20)").Scan(&f))
+		compareDecimal(t, Decimal{Value: bigInt, Width: 29, Scale: 20}, f)
+	})
+
+	t.Run("SELECT DECIMAL types and compare them to FLOAT64", func(t *testing.T) {
+		tests := []struct {
+			input string
+			want  float64
+		}{
+			{input: "1.23::DECIMAL(3, 2)", want: 1.23},
+			{input: "-1.23::DECIMAL(3, 2)", want: -1.23},
+			{input: "123.45::DECIMAL(5, 2)", want: 123.45},
+			{input: "-123.45::DECIMAL(5, 2)", want: -123.45},
+			{input: "123456789.01::DECIMAL(11, 2)", want: 123456789.01},
+			{input: "-123456789.01::DECIMAL(11, 2)", want: -123456789.01},
+			{input: "1234567890123456789.234::DECIMAL(22, 3)", want: 1234567890123456789.234},
+			{input: "-1234567890123456789.234::DECIMAL(22, 3)", want: -1234567890123456789.234},
+			{input: "123456789.01234567890123456789::DECIMAL(29, 20)", want: 123456789.01234567890123456789},
+			{input: "-123456789.01234567890123456789::DECIMAL(29, 20)", want: -123456789.01234567890123456789},
+		}
+		for _, test := range tests {
+			r := db.QueryRow(fmt.Sprintf("SELECT %s", test.input))
+			var fs Decimal
+			require.NoError(t, r.Scan(&fs))
+			require.Equal(t, test.want, fs.Float64())
+		}
+	})
+
+	t.Run("SELECT DECIMAL types and compare them to STRING", func(t *testing.T) {
+		tests := []struct {
+			input string
+			want  string
+		}{
+			{input: "1.23::DECIMAL(3, 2)", want: "1.23"},
+			{input: "-1.23::DECIMAL(3, 2)", want: "-1.23"},
+			{input: "123.45::DECIMAL(5, 2)", want: "123.45"},
+			{input: "-123.45::DECIMAL(5, 2)", want: "-123.45"},
+			{input: "123456789.01::DECIMAL(11, 2)", want: "123456789.01"},
+			{input: "-123456789.01::DECIMAL(11, 2)", want: "-123456789.01"},
+			{input: "1234567890123456789.234::DECIMAL(22, 3)", want: "1234567890123456789.234"},
+			{input: "-1234567890123456789.234::DECIMAL(22, 3)", want: "-1234567890123456789.234"},
+			{input: "123456789.01234567890123456789::DECIMAL(29, 20)", want: "123456789.01234567890123456789"},
+			{input: "-123456789.01234567890123456789::DECIMAL(29, 20)", want: "-123456789.01234567890123456789"},
+		}
+		for _, test := range tests {
+			r := db.QueryRow(fmt.Sprintf("SELECT %s", test.input))
+			var fs Decimal
+			require.NoError(t, r.Scan(&fs))
+			require.Equal(t, test.want, fs.String())
+			// confirms Decimal implements fmt.Stringer correctly (see #424)
+			require.Equal(t, test.want, fmt.Sprint(fs))
+		}
+	})
+}
+
+func TestDecimalString(t *testing.T) {
+	testCases := []struct {
+		input    Decimal
+		expected string
+	}{
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 0,
+				Value: big.NewInt(0),
+			},
+			expected: "0",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 6,
+				Value: big.NewInt(0),
+			},
+			expected: "0",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 0,
+				Value: big.NewInt(1234567890),
+			},
+			expected: "1234567890",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 0,
+				Value: big.NewInt(-1234567890),
+			},
+			expected: "-1234567890",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 1,
+				Value: big.NewInt(1234567890),
+			},
+			expected: "123456789",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 1,
+				Value: big.NewInt(-1234567890),
+			},
+			expected: "-123456789",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 2,
+				Value: big.NewInt(1234567890),
+			},
+			expected: "12345678.9",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 2,
+				Value: big.NewInt(-1234567890),
+			},
+			expected: "-12345678.9",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 6,
+				Value: big.NewInt(1234567890),
+			},
+			expected: "1234.56789",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 6,
+				Value: big.NewInt(-1234567890),
+			},
+			expected: "-1234.56789",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 12,
+				Value: big.NewInt(1234567890),
+			},
+			expected: "0.00123456789",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 12,
+				Value: big.NewInt(-1234567890),
+			},
+			expected: "-0.00123456789",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 1,
+				Value: big.NewInt(1234500000),
+			},
+			expected: "123450000",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 1,
+				Value: big.NewInt(-1234500000),
+			},
+			expected: "-123450000",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 8,
+				Value: big.NewInt(-705399),
+			},
+			expected: "-0.00705399",
+		},
+		{
+			input: Decimal{
+				Width: 18,
+				Scale: 8,
+				Value: big.NewInt(821662),
+			},
+			expected: "0.00821662",
+		},
+	}
+
+	for _, tc := range testCases {
+		actual := tc.input.String()
+		if actual != tc.expected {
+			require.Equal(t, tc.expected, actual)
+		}
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Decimal
+	}{
+		{input: "0", want: Decimal{Width: 1, Scale: 0, Value: big.NewInt(0)}},
+		{input: "1.23", want: Decimal{Width: 3, Scale: 2, Value: big.NewInt(123)}},
+		{input: "-1.23", want: Decimal{Width: 3, Scale: 2, Value: big.NewInt(-123)}},
+		{input: "+0.005", want: Decimal{Width: 3, Scale: 3, Value: big.NewInt(5)}},
+		{input: " 42 ", want: Decimal{Width: 2, Scale: 0, Value: big.NewInt(42)}},
+		{input: ".5", want: Decimal{Width: 1, Scale: 1, Value: big.NewInt(5)}},
+		{input: "1.50", want: Decimal{Width: 3, Scale: 2, Value: big.NewInt(150)}},
+		{input: "1.2345e2", want: Decimal{Width: 5, Scale: 2, Value: big.NewInt(12345)}},
+		{input: "12E-3", want: Decimal{Width: 3, Scale: 3, Value: big.NewInt(12)}},
+		{input: "5e2", want: Decimal{Width: 3, Scale: 0, Value: big.NewInt(500)}},
+	}
+	for _, tc := range tests {
+		d, err := ParseDecimal(tc.input)
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.want, d, tc.input)
+	}
+
+	maxValue, ok := new(big.Int).SetString("99999999999999999999999999999999999999", 10)
+	require.True(t, ok)
+	d, err := ParseDecimal(maxValue.String())
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 38, Scale: 0, Value: maxValue}, d)
+
+	for _, input := range []string{"", "-", ".", "1.2.3", "1e", "abc", "1,5", "0x10"} {
+		_, err = ParseDecimal(input)
+		testError(t, err, errDecimalSyntax.Error())
+	}
+	for _, input := range []string{"1" + maxValue.String(), "1e38", "1e-39"} {
+		_, err = ParseDecimal(input)
+		testError(t, err, errDecimalOverflow.Error())
+	}
+}
+
+func TestNewDecimalFromFloat(t *testing.T) {
+	d, err := NewDecimalFromFloat(2.675, 10, 2)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 10, Scale: 2, Value: big.NewInt(268)}, d)
+
+	d, err = NewDecimalFromFloat(-0.125, 4, 2)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 4, Scale: 2, Value: big.NewInt(-13)}, d)
+
+	d, err = NewDecimalFromFloat(1e-50, 18, 6)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 18, Scale: 6, Value: big.NewInt(0)}, d)
+
+	_, err = NewDecimalFromFloat(1234.5, 4, 1)
+	testError(t, err, errDecimalOverflow.Error())
+	_, err = NewDecimalFromFloat(math.NaN(), 4, 1)
+	testError(t, err, errDecimalNonFinite.Error())
+	_, err = NewDecimalFromFloat(math.Inf(-1), 4, 1)
+	testError(t, err, errDecimalNonFinite.Error())
+	_, err = NewDecimalFromFloat(1, 0, 0)
+	testError(t, err, errAPI.Error(), errInvalidDecimalWidth.Error())
+	_, err = NewDecimalFromFloat(1, 4, 5)
+	testError(t, err, errAPI.Error(), errInvalidDecimalScale.Error())
+}
+
+func TestDecimalRescale(t *testing.T) {
+	d := Decimal{Width: 5, Scale: 3, Value: big.NewInt(12345)}
+
+	r, err := d.Rescale(8, 5)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 8, Scale: 5, Value: big.NewInt(1234500)}, r)
+
+	r, err = d.Rescale(4, 2)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 4, Scale: 2, Value: big.NewInt(1235)}, r)
+
+	r, err = Decimal{Width: 5, Scale: 3, Value: big.NewInt(-12345)}.Rescale(4, 2)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 4, Scale: 2, Value: big.NewInt(-1235)}, r)
+
+	r, err = d.Rescale(2, 0)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 2, Scale: 0, Value: big.NewInt(12)}, r)
+
+	_, err = d.Rescale(3, 3)
+	testError(t, err, errDecimalOverflow.Error())
+	_, err = d.Rescale(39, 3)
+	testError(t, err, errAPI.Error(), errInvalidDecimalWidth.Error())
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a := Decimal{Width: 5, Scale: 2, Value: big.NewInt(12345)}
+	b := Decimal{Width: 4, Scale: 3, Value: big.NewInt(-1005)}
+
+	require.Equal(t, 1, a.Cmp(b))
+	require.Equal(t, -1, b.Cmp(a))
+	require.Equal(t, 0, a.Cmp(Decimal{Width: 10, Scale: 4, Value: big.NewInt(1234500)}))
+	require.Equal(t, 0, Decimal{}.Cmp(Decimal{Width: 3, Scale: 1, Value: big.NewInt(0)}))
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 7, Scale: 3, Value: big.NewInt(122445)}, sum)
+
+	diff, err := a.Sub(b)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 7, Scale: 3, Value: big.NewInt(124455)}, diff)
+
+	prod, err := a.Mul(b)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 9, Scale: 5, Value: big.NewInt(-12406725)}, prod)
+
+	maxValue, err := ParseDecimal("99999999999999999999999999999999999999")
+	require.NoError(t, err)
+	_, err = maxValue.Add(Decimal{Width: 1, Scale: 0, Value: big.NewInt(1)})
+	testError(t, err, errDecimalOverflow.Error())
+	_, err = maxValue.Mul(Decimal{Width: 1, Scale: 0, Value: big.NewInt(2)})
+	testError(t, err, errDecimalOverflow.Error())
+	_, err = Decimal{Width: 38, Scale: 20}.Mul(Decimal{Width: 38, Scale: 20})
+	testError(t, err, errDecimalOverflow.Error())
+}
+
+func TestDecimalRat(t *testing.T) {
+	d := Decimal{Width: 5, Scale: 3, Value: big.NewInt(-12345)}
+	require.Equal(t, 0, big.NewRat(-12345, 1000).Cmp(d.Rat()))
+	require.Equal(t, 0, new(big.Rat).Cmp(Decimal{}.Rat()))
+}
+
+func TestDecimalJSON(t *testing.T) {
+	type payment struct {
+		Amount Decimal  `json:"amount"`
+		Fee    *Decimal `json:"fee"`
+	}
+
+	p := payment{Amount: Decimal{Width: 20, Scale: 2, Value: big.NewInt(-123456789012345678)}}
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	require.Equal(t, `{"amount":-1234567890123456.78,"fee":null}`, string(data))
+
+	var actual payment
+	require.NoError(t, json.Unmarshal(data, &actual))
+	require.Equal(t, 0, p.Amount.Cmp(actual.Amount))
+	require.Nil(t, actual.Fee)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"0.10","fee":"1e-2"}`), &actual))
+	require.Equal(t, Decimal{Width: 2, Scale: 2, Value: big.NewInt(10)}, actual.Amount)
+	require.Equal(t, Decimal{Width: 2, Scale: 2, Value: big.NewInt(1)}, *actual.Fee)
+
+	err = json.Unmarshal([]byte(`{"amount":"ten"}`), &actual)
+	testError(t, err, errDecimalSyntax.Error())
+
+	// The JSON number keeps the scale.
+	for _, tc := range []struct {
+		input    Decimal
+		expected string
+	}{
+		{input: Decimal{Width: 10, Scale: 2, Value: big.NewInt(150)}, expected: "1.50"},
+		{input: Decimal{Width: 10, Scale: 3, Value: big.NewInt(-5)}, expected: "-0.005"},
+		{input: Decimal{Width: 10, Scale: 2, Value: big.NewInt(0)}, expected: "0.00"},
+		{input: Decimal{Width: 10, Scale: 0, Value: big.NewInt(-42)}, expected: "-42"},
+		{input: Decimal{Width: 10, Scale: 1}, expected: "0.0"},
+	} {
+		data, err = json.Marshal(tc.input)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, string(data))
+
+		var d Decimal
+		require.NoError(t, json.Unmarshal(data, &d))
+		require.Equal(t, tc.input.Scale, d.Scale)
+		require.Equal(t, 0, tc.input.Cmp(d))
+	}
+}
+
+func TestDecimalScan(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	var d Decimal
+	require.NoError(t, db.QueryRow(`SELECT '12.50'`).Scan(&d))
+	require.Equal(t, Decimal{Width: 4, Scale: 2, Value: big.NewInt(1250)}, d)
+
+	require.NoError(t, db.QueryRow(`SELECT 42::INTEGER`).Scan(&d))
+	require.Equal(t, Decimal{Width: 2, Scale: 0, Value: big.NewInt(42)}, d)
+
+	require.NoError(t, db.QueryRow(`SELECT 17014118346046923173168730371588410572::HUGEINT`).Scan(&d))
+	require.Equal(t, "17014118346046923173168730371588410572", d.String())
+
+	require.NoError(t, db.QueryRow(`SELECT 0.25::DOUBLE`).Scan(&d))
+	require.Equal(t, Decimal{Width: 2, Scale: 2, Value: big.NewInt(25)}, d)
+
+	require.NoError(t, db.QueryRow(`SELECT 1.23::DECIMAL(10, 2)`).Scan(&d))
+	require.Equal(t, Decimal{Width: 10, Scale: 2, Value: big.NewInt(123)}, d)
+
+	err := db.QueryRow(`SELECT 170141183460469231731687303715884105727::HUGEINT`).Scan(&d)
+	require.ErrorContains(t, err, errDecimalOverflow.Error())
+	err = db.QueryRow(`SELECT 'nan'::DOUBLE`).Scan(&d)
+	require.ErrorContains(t, err, errDecimalNonFinite.Error())
+	err = db.QueryRow(`SELECT true`).Scan(&d)
+	require.ErrorContains(t, err, "invalid type `bool` for scanning `Decimal`")
+}
+
+func TestDecimalBind(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	_, err := db.Exec(`CREATE TABLE payments (amount DECIMAL(10, 2))`)
+	require.NoError(t, err)
+
+	amount, err := ParseDecimal("-1234.56")
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO payments VALUES (?)`, amount)
+	require.NoError(t, err)
+
+	var actual Decimal
+	require.NoError(t, db.QueryRow(`SELECT amount FROM payments`).Scan(&actual))
+	require.Equal(t, Decimal{Width: 10, Scale: 2, Value: big.NewInt(-123456)}, actual)
+
+	var s string
+	require.NoError(t, db.QueryRow(`SELECT ?::VARCHAR`, Decimal{Width: 20, Scale: 3, Value: big.NewInt(1005)}).Scan(&s))
+	require.Equal(t, "1.005", s)
+
+	// A pointer binds like its DECIMAL, or as NULL.
+	_, err = db.Exec(`INSERT INTO payments VALUES (?), (?)`, &Decimal{Width: 10, Scale: 2, Value: big.NewInt(150)}, (*Decimal)(nil))
+	require.NoError(t, err)
+	var amounts string
+	require.NoError(t, db.QueryRow(`SELECT string_agg(coalesce(amount::VARCHAR, 'NULL'), ',' ORDER BY rowid) FROM payments`).Scan(&amounts))
+	require.Equal(t, "-1234.56,1.50,NULL", amounts)
+
+	// A DECIMAL with a value exceeding its width.
+	_, err = db.Exec(`INSERT INTO payments VALUES (?)`, Decimal{Width: 2, Scale: 1, Value: big.NewInt(123)})
+	testError(t, err, errCouldNotBind.Error(), errDecimalOverflow.Error())
+}
+
+func TestAppenderDecimalRescale(t *testing.T) {
+	c, db, conn, a := prepareAppender(t, `CREATE TABLE test (d DECIMAL(8, 2))`)
+	defer cleanupAppender(t, c, db, conn, a)
+
+	require.NoError(t, a.AppendRow(Decimal{Width: 5, Scale: 3, Value: big.NewInt(12345)}))
+	require.NoError(t, a.AppendRow(Decimal{Width: 2, Scale: 0, Value: big.NewInt(-42)}))
+	err := a.AppendRow(Decimal{Width: 10, Scale: 0, Value: big.NewInt(1234567890)})
+	testError(t, err, errAppenderAppendRow.Error(), castErrMsg)
+	require.NoError(t, a.Flush())
+
+	res, err := db.QueryContext(context.Background(), `SELECT d::VARCHAR FROM test ORDER BY d`)
+	require.NoError(t, err)
+	defer closeRowsWrapper(t, res)
+
+	var actual []string
+	for res.Next() {
+		var s string
+		require.NoError(t, res.Scan(&s))
+		actual = append(actual, s)
+	}
+	require.Equal(t, []string{"-42.00", "12.35"}, actual)
+}
@@ -0,0 +1,464 @@
+package duckdb
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// decodeFn decodes a scanned value into dst.
+// The path names the position of dst in the scanned value, e.g., "Items[2].Name".
+type decodeFn func(src any, dst reflect.Value, path string) error
+
+// decoderCache caches the decodeFn of each destination type.
+var decoderCache sync.Map
+
+var scannerType = reflect.TypeFor[sql.Scanner]()
+
+// decodeValue decodes a scanned value into dst, which must be a non-nil pointer.
+// See Composite for the decoding rules.
+func decodeValue(src any, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("cannot decode into `%T`, expected a non-nil pointer", dst)
+	}
+	return decoderFor(v.Type().Elem())(src, v.Elem(), "")
+}
+
+func decoderFor(t reflect.Type) decodeFn {
+	if fn, ok := decoderCache.Load(t); ok {
+		return fn.(decodeFn)
+	}
+
+	// Recursive types refer to their own decodeFn while building it.
+	// Until it is complete, they wait for it through an indirection.
+	var wg sync.WaitGroup
+	var fn decodeFn
+	wg.Add(1)
+	indirect, loaded := decoderCache.LoadOrStore(t, decodeFn(func(src any, dst reflect.Value, path string) error {
+		wg.Wait()
+		return fn(src, dst, path)
+	}))
+	if loaded {
+		return indirect.(decodeFn)
+	}
+
+	fn = newDecoder(t)
+	wg.Done()
+	decoderCache.Store(t, fn)
+	return fn
+}
+
+func newDecoder(t reflect.Type) decodeFn {
+	decode := newKindDecoder(t)
+	scanner := reflect.PointerTo(t).Implements(scannerType)
+
+	return func(src any, dst reflect.Value, path string) error {
+		if src == nil {
+			dst.SetZero()
+			return nil
+		}
+		srcValue := reflect.ValueOf(src)
+		if srcValue.Type().AssignableTo(t) {
+			dst.Set(srcValue)
+			return nil
+		}
+		if scanner {
+			if err := dst.Addr().Interface().(sql.Scanner).Scan(src); err != nil {
+				return decodeError(path, err)
+			}
+			return nil
+		}
+		return decode(srcValue, dst, path)
+	}
+}
+
+// kindDecodeFn decodes a non-nil value, which is not assignable to dst.
+type kindDecodeFn func(src reflect.Value, dst reflect.Value, path string) error
+
+func newKindDecoder(t reflect.Type) kindDecodeFn {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return newPointerDecoder(t)
+	case reflect.Struct:
+		return newStructDecoder(t)
+	case reflect.Slice, reflect.Array:
+		return newSliceDecoder(t)
+	case reflect.Map:
+		return newMapDecoder(t)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decodeInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return decodeUint
+	case reflect.Float32, reflect.Float64:
+		return decodeFloat
+	case reflect.String, reflect.Bool:
+		return decodeConvertible
+	default:
+		return decodeMismatch
+	}
+}
+
+func newPointerDecoder(t reflect.Type) kindDecodeFn {
+	elem := decoderFor(t.Elem())
+	return func(src reflect.Value, dst reflect.Value, path string) error {
+		ptr := reflect.New(t.Elem())
+		if err := elem(src.Interface(), ptr.Elem(), path); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+		return nil
+	}
+}
+
+// structField is a field of the decoding plan of a struct.
+type structField struct {
+	// The STRUCT entry name of the field.
+	name string
+	// The index sequence of the field, see reflect.Value.FieldByIndex.
+	index []int
+	// The Go name of the field, for error messages.
+	goName string
+	// The type of the field.
+	fieldType reflect.Type
+	// The column type of the `type` tag option, if any.
+	columnType string
+	decode     decodeFn
+}
+
+func newStructDecoder(t reflect.Type) kindDecodeFn {
+	fields := structFields(t)
+	for i := range fields {
+		fields[i].decode = decoderFor(t.FieldByIndex(fields[i].index).Type)
+	}
+
+	return func(src reflect.Value, dst reflect.Value, path string) error {
+		m, ok := src.Interface().(map[string]any)
+		if !ok {
+			return decodeMismatch(src, dst, path)
+		}
+
+		for i := range fields {
+			field := &fields[i]
+			v, ok := m[field.name]
+			if !ok {
+				if v, ok = lookupFold(m, field.name); !ok {
+					continue
+				}
+			}
+			fieldValue, err := fieldByIndexAlloc(dst, field.index)
+			if err != nil {
+				return decodeError(joinPath(path, field.goName), err)
+			}
+			if err = field.decode(v, fieldValue, joinPath(path, field.goName)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// structFields returns the decodable fields of a struct type, including the promoted fields of embedded structs.
+// Like encoding/json, a field with a shallower depth hides promoted fields with the same name.
+func structFields(t reflect.Type) []structField {
+	type candidate struct {
+		structField
+		depth int
+	}
+
+	var candidates []candidate
+	var collect func(t reflect.Type, index []int, depth int)
+	collect = func(t reflect.Type, index []int, depth int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag, hasTag, opts := fieldTag(f)
+			if tag == "-" {
+				continue
+			}
+			fieldIndex := append(append([]int{}, index...), i)
+
+			fieldType := f.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if ((f.Anonymous && !hasTag) || opts.squash) && fieldType.Kind() == reflect.Struct {
+				collect(fieldType, fieldIndex, depth+1)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+
+			name := f.Name
+			if hasTag && tag != "" {
+				name = tag
+			}
+			candidates = append(candidates, candidate{
+				structField: structField{name: name, index: fieldIndex, goName: f.Name, fieldType: f.Type, columnType: opts.columnType},
+				depth:       depth,
+			})
+		}
+	}
+	collect(t, nil, 0)
+
+	var fields []structField
+	for i, c := range candidates {
+		hidden := false
+		for j, other := range candidates {
+			if i != j && strings.EqualFold(c.name, other.name) && (other.depth < c.depth || (other.depth == c.depth && j < i)) {
+				hidden = true
+				break
+			}
+		}
+		if !hidden {
+			fields = append(fields, c.structField)
+		}
+	}
+	return fields
+}
+
+// tagOptions are the options of a struct field's tag.
+type tagOptions struct {
+	// squash promotes the fields of a struct field.
+	squash bool
+	// columnType is the column type of the field, see EnsureTable.
+	columnType string
+}
+
+// fieldTag returns the entry name of a struct field's tag, whether the field has a tag, and the tag's options.
+// The `duckdb` tag supports the `type` option, e.g., `duckdb:"amount,type=DECIMAL(38,10)"`, which must be
+// the last option, as the type can contain commas.
+// Without a `duckdb` tag, it falls back to the `mapstructure` tag, whose `squash` option promotes the fields
+// of a struct field.
+func fieldTag(f reflect.StructField) (name string, ok bool, opts tagOptions) {
+	if tag, ok := f.Tag.Lookup("duckdb"); ok {
+		name, options, _ := strings.Cut(tag, ",")
+		if columnType, found := strings.CutPrefix(options, "type="); found {
+			opts.columnType = columnType
+		}
+		return name, true, opts
+	}
+	tag, ok := f.Tag.Lookup("mapstructure")
+	if !ok {
+		return "", false, opts
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	for _, option := range strings.Split(options, ",") {
+		if option == "squash" {
+			opts.squash = true
+		}
+	}
+	return name, true, opts
+}
+
+// fieldByIndexAlloc returns the nested field of v, and allocates nil pointers to embedded structs.
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct `%s`", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v, nil
+}
+
+func lookupFold(m map[string]any, name string) (any, bool) {
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func newSliceDecoder(t reflect.Type) kindDecodeFn {
+	elem := decoderFor(t.Elem())
+	return func(src reflect.Value, dst reflect.Value, path string) error {
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			return decodeMismatch(src, dst, path)
+		}
+
+		n := src.Len()
+		target := dst
+		if t.Kind() == reflect.Array {
+			if n != t.Len() {
+				return decodeError(path, invalidInputError(strconv.Itoa(n), strconv.Itoa(t.Len())))
+			}
+			target = reflect.New(t).Elem()
+		} else {
+			target = reflect.MakeSlice(t, n, n)
+		}
+
+		for i := 0; i < n; i++ {
+			if err := elem(src.Index(i).Interface(), target.Index(i), path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+		dst.Set(target)
+		return nil
+	}
+}
+
+func newMapDecoder(t reflect.Type) kindDecodeFn {
+	key := decoderFor(t.Key())
+	elem := decoderFor(t.Elem())
+	return func(src reflect.Value, dst reflect.Value, path string) error {
+		var entries OrderedMap
+		switch v := src.Interface().(type) {
+		case OrderedMap:
+			entries = v
+		case Map, map[string]any:
+			// A STRUCT value can also decode into a map.
+			entries, _ = extractMapEntries(v)
+		default:
+			return decodeMismatch(src, dst, path)
+		}
+
+		target := reflect.MakeMapWithSize(t, len(entries))
+		k := reflect.New(t.Key()).Elem()
+		v := reflect.New(t.Elem()).Elem()
+		for _, entry := range entries {
+			k.SetZero()
+			v.SetZero()
+			entryPath := fmt.Sprintf("%s[%v]", path, entry.Key)
+			if err := key(entry.Key, k, entryPath); err != nil {
+				return err
+			}
+			if err := elem(entry.Value, v, entryPath); err != nil {
+				return err
+			}
+			target.SetMapIndex(k, v)
+		}
+		dst.Set(target)
+		return nil
+	}
+}
+
+func decodeInt(src reflect.Value, dst reflect.Value, path string) error {
+	var i int64
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i = src.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := src.Uint()
+		if u > math.MaxInt64 {
+			return decodeOverflow(src, dst, path)
+		}
+		i = int64(u)
+	case reflect.Float32, reflect.Float64:
+		// E.g., JSON numbers.
+		f := src.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return decodeMismatch(src, dst, path)
+		}
+		i = int64(f)
+	default:
+		b, ok := src.Interface().(*big.Int)
+		if !ok {
+			return decodeMismatch(src, dst, path)
+		}
+		if !b.IsInt64() {
+			return decodeOverflow(src, dst, path)
+		}
+		i = b.Int64()
+	}
+	if dst.OverflowInt(i) {
+		return decodeOverflow(src, dst, path)
+	}
+	dst.SetInt(i)
+	return nil
+}
+
+func decodeUint(src reflect.Value, dst reflect.Value, path string) error {
+	var u uint64
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := src.Int()
+		if i < 0 {
+			return decodeOverflow(src, dst, path)
+		}
+		u = uint64(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u = src.Uint()
+	case reflect.Float32, reflect.Float64:
+		f := src.Float()
+		if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+			return decodeMismatch(src, dst, path)
+		}
+		u = uint64(f)
+	default:
+		b, ok := src.Interface().(*big.Int)
+		if !ok {
+			return decodeMismatch(src, dst, path)
+		}
+		if !b.IsUint64() {
+			return decodeOverflow(src, dst, path)
+		}
+		u = b.Uint64()
+	}
+	if dst.OverflowUint(u) {
+		return decodeOverflow(src, dst, path)
+	}
+	dst.SetUint(u)
+	return nil
+}
+
+func decodeFloat(src reflect.Value, dst reflect.Value, path string) error {
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		dst.SetFloat(float64(src.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		dst.SetFloat(float64(src.Uint()))
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(src.Float())
+	default:
+		d, ok := src.Interface().(Decimal)
+		if !ok {
+			return decodeMismatch(src, dst, path)
+		}
+		dst.SetFloat(d.Float64())
+	}
+	return nil
+}
+
+// decodeConvertible decodes values of the same kind, e.g., an ENUM string into a named string type.
+func decodeConvertible(src reflect.Value, dst reflect.Value, path string) error {
+	if src.Kind() != dst.Kind() {
+		return decodeMismatch(src, dst, path)
+	}
+	dst.Set(src.Convert(dst.Type()))
+	return nil
+}
+
+func decodeMismatch(src reflect.Value, dst reflect.Value, path string) error {
+	return decodeError(path, castError(src.Type().String(), dst.Type().String()))
+}
+
+func decodeOverflow(src reflect.Value, dst reflect.Value, path string) error {
+	return decodeError(path, fmt.Errorf("%s: %v overflows %s", convertErrMsg, src.Interface(), dst.Type()))
+}
+
+func decodeError(path string, err error) error {
+	if path == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", path, err)
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
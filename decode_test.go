@@ -0,0 +1,173 @@
+package duckdb
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type decodeBase struct {
+	ID      int64 `duckdb:"id"`
+	Created time.Time
+}
+
+// DecodeAudit is exported, as decoding allocates embedded struct pointers.
+type DecodeAudit struct {
+	By string
+}
+
+type decodeItem struct {
+	Name  string
+	Tags  []string
+	Price *Decimal
+}
+
+type decodeOrder struct {
+	decodeBase
+	*DecodeAudit
+	Customer string         `duckdb:"customer_name"`
+	Items    []decodeItem   `duckdb:"items"`
+	Dims     [2]int16       `duckdb:"dims"`
+	Counts   map[string]int `duckdb:"counts"`
+	Ignored  string         `duckdb:"-"`
+	internal string
+}
+
+type decodeTree struct {
+	Value    int32
+	Children []decodeTree
+	Parent   *decodeTree
+}
+
+type decodePoint struct {
+	X int32
+	Y int32
+}
+
+type decodeStatus string
+
+type decodeLegacyMeta struct {
+	Source string `mapstructure:"src"`
+}
+
+type decodeLegacy struct {
+	ID      int64            `mapstructure:"user_id"`
+	Name    string           `duckdb:"name" mapstructure:"ignored"`
+	Meta    decodeLegacyMeta `mapstructure:",squash"`
+	Skipped string           `mapstructure:"-"`
+}
+
+func TestComposite(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	t.Run("struct", func(t *testing.T) {
+		var res Composite[decodeOrder]
+		require.NoError(t, db.QueryRow(`SELECT {
+			'id': 42,
+			'Created': TIMESTAMP '2024-01-02 03:04:05',
+			'by': 'admin',
+			'customer_name': 'Ada',
+			'items': [{'name': 'pen', 'tags': ['blue'], 'price': 1.25::DECIMAL(5, 2)}, {'name': 'ink', 'tags': [], 'price': NULL}],
+			'dims': [3, 4],
+			'counts': MAP(['a'], [7]),
+			'Ignored': 'nope',
+			'internal': 'nope',
+			'extra': 1
+		}`).Scan(&res))
+
+		price := Decimal{Width: 5, Scale: 2, Value: big.NewInt(125)}
+		require.Equal(t, decodeOrder{
+			decodeBase:  decodeBase{ID: 42, Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+			DecodeAudit: &DecodeAudit{By: "admin"},
+			Customer:    "Ada",
+			Items: []decodeItem{
+				{Name: "pen", Tags: []string{"blue"}, Price: &price},
+				{Name: "ink", Tags: []string{}},
+			},
+			Dims:   [2]int16{3, 4},
+			Counts: map[string]int{"a": 7},
+		}, res.Get())
+	})
+
+	t.Run("recursive struct", func(t *testing.T) {
+		var res Composite[decodeTree]
+		require.NoError(t, db.QueryRow(`SELECT {'value': 1, 'children': [{'value': 2, 'children': NULL, 'parent': NULL}], 'parent': {'value': 0}}`).Scan(&res))
+		require.Equal(t, decodeTree{
+			Value:    1,
+			Children: []decodeTree{{Value: 2}},
+			Parent:   &decodeTree{},
+		}, res.Get())
+	})
+
+	t.Run("map with STRUCT keys", func(t *testing.T) {
+		var res Composite[map[decodePoint][]decodeStatus]
+		require.NoError(t, db.QueryRow(`SELECT MAP([{'x': 1, 'y': 2}], [['open', 'closed']])`).Scan(&res))
+		require.Equal(t, map[decodePoint][]decodeStatus{{X: 1, Y: 2}: {"open", "closed"}}, res.Get())
+
+		var typed TypedMap[decodePoint, int64]
+		require.NoError(t, db.QueryRow(`SELECT MAP([{'x': 3, 'y': 4}], [5])`).Scan(&typed))
+		require.Equal(t, TypedMap[decodePoint, int64]{{X: 3, Y: 4}: 5}, typed)
+	})
+
+	t.Run("STRUCT into map", func(t *testing.T) {
+		var res Composite[map[string]float64]
+		require.NoError(t, db.QueryRow(`SELECT {'a': 1, 'b': 2.5}`).Scan(&res))
+		require.Equal(t, map[string]float64{"a": 1, "b": 2.5}, res.Get())
+	})
+
+	t.Run("mapstructure tags", func(t *testing.T) {
+		var res Composite[decodeLegacy]
+		require.NoError(t, db.QueryRow(`SELECT {'user_id': 7, 'name': 'Ada', 'src': 'import', 'skipped': 'nope'}`).Scan(&res))
+		require.Equal(t, decodeLegacy{ID: 7, Name: "Ada", Meta: decodeLegacyMeta{Source: "import"}}, res.Get())
+	})
+
+	t.Run("reuse", func(t *testing.T) {
+		var res Composite[decodeItem]
+		require.NoError(t, db.QueryRow(`SELECT {'name': 'pen', 'tags': ['blue']}`).Scan(&res))
+		require.NoError(t, db.QueryRow(`SELECT {'name': 'ink'}`).Scan(&res))
+		require.Equal(t, decodeItem{Name: "ink"}, res.Get())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var res Composite[decodeOrder]
+		err := db.QueryRow(`SELECT {'items': [{'name': 42}]}`).Scan(&res)
+		require.ErrorContains(t, err, "Items[0].Name: "+castErrMsg+": cannot cast int32 to string")
+
+		err = db.QueryRow(`SELECT {'dims': [1, 2, 3]}`).Scan(&res)
+		require.ErrorContains(t, err, "Dims: "+invalidInputErrMsg)
+
+		err = db.QueryRow(`SELECT {'dims': [1, 100000]}`).Scan(&res)
+		require.ErrorContains(t, err, "Dims[1]: "+convertErrMsg)
+
+		err = db.QueryRow(`SELECT {'counts': MAP(['a'], ['x'])}`).Scan(&res)
+		require.ErrorContains(t, err, "Counts[a]: "+castErrMsg)
+
+		var list Composite[[]uint8]
+		err = db.QueryRow(`SELECT [1, -1]`).Scan(&list)
+		require.ErrorContains(t, err, "[1]: "+convertErrMsg)
+	})
+}
+
+func TestDecodeValue(t *testing.T) {
+	// JSON numbers are float64 values.
+	var ints []int
+	require.NoError(t, decodeValue([]any{float64(1), float64(2)}, &ints))
+	require.Equal(t, []int{1, 2}, ints)
+	require.ErrorContains(t, decodeValue([]any{1.5}, &ints), "[0]: "+castErrMsg)
+
+	// HUGEINT values.
+	var i64 int64
+	require.NoError(t, decodeValue(big.NewInt(-7), &i64))
+	require.Equal(t, int64(-7), i64)
+
+	var ptr *int32
+	require.NoError(t, decodeValue(int32(3), &ptr))
+	require.Equal(t, int32(3), *ptr)
+	require.NoError(t, decodeValue(nil, &ptr))
+	require.Nil(t, ptr)
+
+	require.ErrorContains(t, decodeValue(1, ints), "expected a non-nil pointer")
+}
@@ -10,8 +10,10 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/marcboeker/go-duckdb/mapping"
 )
@@ -21,6 +23,15 @@ var GetInstanceCache = sync.OnceValue[mapping.InstanceCache](
 		return mapping.CreateInstanceCache()
 	})
 
+const (
+	// dsnTimestampTZLocation is the DSN option setting the location of scanned TIMESTAMPTZ values.
+	dsnTimestampTZLocation = "timestamptz_location"
+	// dsnSessionTimeZone is the value of dsnTimestampTZLocation selecting the connection's TimeZone setting.
+	dsnSessionTimeZone = "session"
+	// dsnOrderedMaps is the DSN option returning all MAP values as an OrderedMap.
+	dsnOrderedMaps = "ordered_maps"
+)
+
 func init() {
 	sql.Register("duckdb", Driver{})
 }
@@ -62,6 +73,16 @@ func NewConnector(dsn string, connInitFn func(execer driver.ExecerContext) error
 		return nil, getError(errParseDSN, err)
 	}
 
+	// Extract the driver options, which are not DuckDB configuration options.
+	tzLocation, tzSession, err := extractTimestampTZLocation(parsedDSN)
+	if err != nil {
+		return nil, getError(errParseDSN, err)
+	}
+	orderedMaps, err := extractOrderedMaps(parsedDSN)
+	if err != nil {
+		return nil, getError(errParseDSN, err)
+	}
+
 	config, err := prepareConfig(parsedDSN)
 	if err != nil {
 		return nil, err
@@ -85,8 +106,11 @@ func NewConnector(dsn string, connInitFn func(execer driver.ExecerContext) error
 	}
 
 	return &Connector{
-		db:         db,
-		connInitFn: connInitFn,
+		db:          db,
+		connInitFn:  connInitFn,
+		tzLocation:  tzLocation,
+		tzSession:   tzSession,
+		orderedMaps: orderedMaps,
 	}, nil
 }
 
@@ -94,6 +118,12 @@ type Connector struct {
 	closed     bool
 	db         mapping.Database
 	connInitFn func(execer driver.ExecerContext) error
+	// tzLocation is the location of scanned TIMESTAMPTZ values. If nil, they are in UTC.
+	tzLocation *time.Location
+	// tzSession is true, if scanned TIMESTAMPTZ values are in the location of the connection's TimeZone setting.
+	tzSession bool
+	// orderedMaps is true, if scanned MAP values are always an OrderedMap.
+	orderedMaps bool
 }
 
 func (*Connector) Driver() driver.Driver {
@@ -106,7 +136,12 @@ func (c *Connector) Connect(context.Context) (driver.Conn, error) {
 		return nil, getError(errConnect, nil)
 	}
 
-	conn := &Conn{conn: newConn}
+	conn := &Conn{
+		conn:        newConn,
+		tzLocation:  c.tzLocation,
+		tzSession:   c.tzSession,
+		orderedMaps: c.orderedMaps,
+	}
 	if c.connInitFn != nil {
 		if err := c.connInitFn(conn); err != nil {
 			return nil, err
@@ -116,6 +151,27 @@ func (c *Connector) Connect(context.Context) (driver.Conn, error) {
 	return conn, nil
 }
 
+// SetTimestampTZLocation sets the location of scanned TIMESTAMPTZ values for all subsequently created connections.
+// A nil location restores the default, which returns TIMESTAMPTZ values in UTC.
+func (c *Connector) SetTimestampTZLocation(loc *time.Location) {
+	c.tzLocation = loc
+	c.tzSession = false
+}
+
+// UseSessionTimeZone returns scanned TIMESTAMPTZ values in the location of the connection's TimeZone setting,
+// e.g., after executing SET TimeZone = 'Europe/Berlin'. It applies to all subsequently created connections.
+func (c *Connector) UseSessionTimeZone() {
+	c.tzLocation = nil
+	c.tzSession = true
+}
+
+// UseOrderedMaps returns all scanned MAP values as an OrderedMap, which preserves the order of their entries.
+// By default, only MAP values with keys that are not comparable in Go are an OrderedMap, and all others are a Map.
+// It applies to all subsequently created connections.
+func (c *Connector) UseOrderedMaps() {
+	c.orderedMaps = true
+}
+
 func (c *Connector) Close() error {
 	if c.closed {
 		return nil
@@ -134,6 +190,44 @@ func getDBPath(dsn string) string {
 	return dsn[0:idx]
 }
 
+// extractTimestampTZLocation removes the timestamptz_location option from the DSN and returns its location.
+// The special value 'session' selects the connection's TimeZone setting.
+func extractTimestampTZLocation(parsedDSN *url.URL) (*time.Location, bool, error) {
+	query := parsedDSN.Query()
+	if !query.Has(dsnTimestampTZLocation) {
+		return nil, false, nil
+	}
+	name := query.Get(dsnTimestampTZLocation)
+	query.Del(dsnTimestampTZLocation)
+	parsedDSN.RawQuery = query.Encode()
+
+	if name == dsnSessionTimeZone {
+		return nil, true, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s=%s: %w", dsnTimestampTZLocation, name, err)
+	}
+	return loc, false, nil
+}
+
+// extractOrderedMaps removes the ordered_maps option from the DSN and returns its value.
+func extractOrderedMaps(parsedDSN *url.URL) (bool, error) {
+	query := parsedDSN.Query()
+	if !query.Has(dsnOrderedMaps) {
+		return false, nil
+	}
+	value := query.Get(dsnOrderedMaps)
+	query.Del(dsnOrderedMaps)
+	parsedDSN.RawQuery = query.Encode()
+
+	orderedMaps, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s=%s: %w", dsnOrderedMaps, value, err)
+	}
+	return orderedMaps, nil
+}
+
 func prepareConfig(parsedDSN *url.URL) (mapping.Config, error) {
 	var config mapping.Config
 	if mapping.CreateConfig(&config) == mapping.StateError {
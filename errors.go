@@ -109,17 +109,29 @@ var (
 	errReadOnlyTxNotSupported     = errors.New("read-only transactions are not supported")
 	errIsolationLevelNotSupported = errors.New("isolation level not supported: go-duckdb only supports the default isolation level")
 
-	errAppenderCreation         = errors.New("could not create appender")
-	errAppenderClose            = errors.New("could not close appender")
-	errAppenderDoubleClose      = fmt.Errorf("%w: already closed", errAppenderClose)
-	errAppenderAppendRow        = errors.New("could not append row")
-	errAppenderAppendAfterClose = fmt.Errorf("%w: appender already closed", errAppenderAppendRow)
-	errAppenderFlush            = errors.New("could not flush appender")
+	errAppenderCreation               = errors.New("could not create appender")
+	errAppenderClose                  = errors.New("could not close appender")
+	errAppenderDoubleClose            = fmt.Errorf("%w: already closed", errAppenderClose)
+	errAppenderAppendRow              = errors.New("could not append row")
+	errAppenderAppendAfterClose       = fmt.Errorf("%w: appender already closed", errAppenderAppendRow)
+	errAppenderFlush                  = errors.New("could not flush appender")
+	errAppenderAppendChunk            = errors.New("could not append chunk")
+	errAppenderAppendChunkAfterClose  = fmt.Errorf("%w: appender already closed", errAppenderAppendChunk)
+	errAppenderAppendRecord           = errors.New("could not append record")
+	errAppenderAppendRecordAfterClose = fmt.Errorf("%w: appender already closed", errAppenderAppendRecord)
+	errAppenderUnknownColumn          = errors.New("unknown column")
+	errEnsureTable                    = errors.New("could not create or alter table")
 
 	errUnsupportedMapKeyType = errors.New("MAP key type not supported")
 	errEmptyName             = errors.New("empty name")
 	errInvalidDecimalWidth   = fmt.Errorf("the DECIMAL with must be between 1 and %d", max_decimal_width)
 	errInvalidDecimalScale   = errors.New("the DECIMAL scale must be less than or equal to the width")
+	errDecimalSyntax         = errors.New("invalid DECIMAL syntax")
+	errDecimalOverflow       = errors.New("the value does not fit into the DECIMAL width")
+	errDecimalNonFinite      = errors.New("cannot convert NaN or Inf to DECIMAL")
+	errIntervalSyntax        = errors.New("invalid ISO 8601 duration")
+	errIntervalMonths        = errors.New("cannot convert an INTERVAL with months to a time.Duration")
+	errIntervalOverflow      = errors.New("the INTERVAL is out of range")
 	errInvalidArraySize      = errors.New("invalid ARRAY size")
 	errSetSQLNULLValue       = errors.New("cannot write to a NULL column")
 
@@ -139,6 +151,10 @@ var (
 	errTableUDFArgumentIsNil   = fmt.Errorf("%w: argument is nil", errTableUDFCreate)
 	errTableUDFColumnTypeIsNil = fmt.Errorf("%w: column type is nil", errTableUDFCreate)
 
+	errArrowSchemaMismatch    = errors.New("the Arrow schema does not match the expected schema")
+	errArrowNoRecords         = errors.New("no Arrow records to register")
+	errArrowTableUnregistered = errors.New("the Arrow table has been unregistered")
+
 	errProfilingInfoEmpty = errors.New("no profiling information available for this connection")
 )
 
@@ -0,0 +1,331 @@
+package duckdb
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcboeker/go-duckdb/mapping"
+)
+
+const (
+	microsPerSecond = int64(time.Second / time.Microsecond)
+	microsPerMinute = 60 * microsPerSecond
+	microsPerHour   = 60 * microsPerMinute
+	microsPerDay    = 24 * microsPerHour
+	daysPerMonth    = 30
+)
+
+// Interval is a DuckDB INTERVAL. Like in DuckDB, its months, days, and microseconds are independent components,
+// as the length of a month or a day depends on the point in time it is added to.
+type Interval struct {
+	Days   int32 `json:"days"`
+	Months int32 `json:"months"`
+	Micros int64 `json:"micros"`
+}
+
+// NewIntervalFromDuration returns an Interval containing d, truncated to microseconds.
+func NewIntervalFromDuration(d time.Duration) Interval {
+	return Interval{Micros: d.Microseconds()}
+}
+
+// ParseInterval parses an ISO 8601 duration, e.g., "P1Y2M3DT4H5M6.5S".
+// It accepts weeks, signed components, and a leading sign negating the whole duration, e.g., "-P1W" or "P1DT-2H".
+// Only the seconds can have a fraction, which is truncated to microseconds.
+func ParseInterval(s string) (Interval, error) {
+	i, ok := parseInterval(strings.TrimSpace(s))
+	if !ok {
+		return Interval{}, getError(errIntervalSyntax, fmt.Errorf("%q", s))
+	}
+	return i, nil
+}
+
+// Duration converts i to a time.Duration, where a day has 24 hours.
+// It fails for intervals with a month component, as their duration is not fixed.
+func (i Interval) Duration() (time.Duration, error) {
+	if i.Months != 0 {
+		return 0, getError(errIntervalMonths, fmt.Errorf("%s", i.String()))
+	}
+	micros, ok := mulAddInt64(i.Micros, int64(i.Days), microsPerDay)
+	if !ok {
+		return 0, getError(errIntervalOverflow, fmt.Errorf("%s", i.String()))
+	}
+	nanos, ok := mulAddInt64(0, micros, int64(time.Microsecond))
+	if !ok {
+		return 0, getError(errIntervalOverflow, fmt.Errorf("%s", i.String()))
+	}
+	return time.Duration(nanos), nil
+}
+
+// AddTo returns t + i. Like DuckDB, it first adds the months, then the days, and then the microseconds.
+// Adding months clamps the day to the end of the resulting month, e.g., January 31 plus one month is February 28 or 29.
+// Months and days follow the calendar of t's location.
+func (i Interval) AddTo(t time.Time) time.Time {
+	if i.Months != 0 {
+		year, month, day := t.Date()
+		hour, minute, sec := t.Clock()
+		month += time.Month(i.Months)
+		// Day zero of the next month is the last day of this month.
+		lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, t.Location()).Day()
+		t = time.Date(year, month, min(day, lastDay), hour, minute, sec, t.Nanosecond(), t.Location())
+	}
+	t = t.AddDate(0, 0, int(i.Days))
+	return t.Add(time.Duration(i.Micros) * time.Microsecond)
+}
+
+// Normalize carries whole days of the microseconds into the days, and whole months of the days into the months.
+// Like DuckDB, it assumes that a day has 24 hours and a month has 30 days.
+// The normalized interval compares equal to i in DuckDB, but it can yield a different result in AddTo.
+func (i Interval) Normalize() Interval {
+	days := int64(i.Days) + i.Micros/microsPerDay
+	months := int64(i.Months) + days/daysPerMonth
+	return Interval{
+		Months: int32(max(min(months, math.MaxInt32), math.MinInt32)),
+		Days:   int32(days % daysPerMonth),
+		Micros: i.Micros % microsPerDay,
+	}
+}
+
+// String returns i as an ISO 8601 duration, e.g., "P1Y2M3DT4H5M6.5S".
+// Components are signed individually, and zero components are omitted.
+func (i Interval) String() string {
+	if i == (Interval{}) {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	b.WriteByte('P')
+	writeIntervalComponent(&b, int64(i.Months/12), 'Y')
+	writeIntervalComponent(&b, int64(i.Months%12), 'M')
+	writeIntervalComponent(&b, int64(i.Days), 'D')
+	if i.Micros == 0 {
+		return b.String()
+	}
+
+	b.WriteByte('T')
+	writeIntervalComponent(&b, i.Micros/microsPerHour, 'H')
+	writeIntervalComponent(&b, i.Micros%microsPerHour/microsPerMinute, 'M')
+
+	micros := i.Micros % microsPerMinute
+	if micros == 0 {
+		return b.String()
+	}
+	if micros < 0 {
+		b.WriteByte('-')
+		micros = -micros
+	}
+	b.WriteString(strconv.FormatInt(micros/microsPerSecond, 10))
+	if frac := micros % microsPerSecond; frac != 0 {
+		b.WriteByte('.')
+		b.WriteString(strings.TrimRight(fmt.Sprintf("%06d", frac), "0"))
+	}
+	b.WriteByte('S')
+	return b.String()
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts INTERVAL values and ISO 8601 durations.
+func (i *Interval) Scan(v any) error {
+	var err error
+	switch val := v.(type) {
+	case Interval:
+		*i = val
+	case string:
+		*i, err = ParseInterval(val)
+	case []byte:
+		*i, err = ParseInterval(string(val))
+	default:
+		return fmt.Errorf("invalid type `%T` for scanning `Interval`", v)
+	}
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+// The driver binds Interval values directly, so this is only used when converting
+// through the database/sql default converter, e.g., for *Interval.
+// It returns a string that DuckDB can cast to an INTERVAL, as DuckDB does not parse ISO 8601 durations.
+func (i Interval) Value() (driver.Value, error) {
+	return fmt.Sprintf("%d months %d days %d microseconds", i.Months, i.Days, i.Micros), nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface by returning the ISO 8601 duration.
+func (i Interval) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface by parsing an ISO 8601 duration.
+func (i *Interval) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = ParseInterval(string(text))
+	return err
+}
+
+// intervalJSON has the same fields as Interval, but not its methods.
+type intervalJSON Interval
+
+// MarshalJSON implements the json.Marshaler interface.
+// It keeps encoding the interval as an object, instead of its text representation.
+func (i Interval) MarshalJSON() ([]byte, error) {
+	return json.Marshal(intervalJSON(i))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts objects and ISO 8601 duration strings.
+func (i *Interval) UnmarshalJSON(data []byte) error {
+	if strings.HasPrefix(string(data), `"`) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return i.UnmarshalText([]byte(s))
+	}
+	return json.Unmarshal(data, (*intervalJSON)(i))
+}
+
+func (i *Interval) getMappedInterval() *mapping.Interval {
+	return mapping.NewInterval(i.Months, i.Days, i.Micros)
+}
+
+func writeIntervalComponent(b *strings.Builder, n int64, designator byte) {
+	if n == 0 {
+		return
+	}
+	b.WriteString(strconv.FormatInt(n, 10))
+	b.WriteByte(designator)
+}
+
+func parseInterval(s string) (Interval, bool) {
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" || (s[0] != 'P' && s[0] != 'p') {
+		return Interval{}, false
+	}
+	s = s[1:]
+
+	// The designators must appear in this order.
+	const dateDesignators, timeDesignators = "YMWD", "HMS"
+	designators, next := dateDesignators, 0
+	var months, days, micros int64
+	components := 0
+
+	for s != "" {
+		if s[0] == 'T' || s[0] == 't' {
+			if designators == timeDesignators || len(s) == 1 {
+				return Interval{}, false
+			}
+			designators, next = timeDesignators, 0
+			s = s[1:]
+		}
+
+		// Split the component into its number and its designator.
+		end := strings.IndexFunc(s, func(r rune) bool { return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' })
+		if end <= 0 {
+			return Interval{}, false
+		}
+		num, designator := s[:end], s[end]&^0x20
+		s = s[end+1:]
+
+		idx := strings.IndexByte(designators[next:], designator)
+		if idx == -1 {
+			return Interval{}, false
+		}
+		next += idx + 1
+		components++
+
+		var ok bool
+		if designators == timeDesignators && designator == 'S' {
+			var secs int64
+			if secs, ok = parseIntervalSeconds(num); !ok {
+				return Interval{}, false
+			}
+			if micros, ok = mulAddInt64(micros, secs, 1); !ok {
+				return Interval{}, false
+			}
+			continue
+		}
+
+		n, err := strconv.ParseInt(num, 10, 64)
+		if err != nil {
+			return Interval{}, false
+		}
+		switch {
+		case designators == timeDesignators && designator == 'H':
+			micros, ok = mulAddInt64(micros, n, microsPerHour)
+		case designators == timeDesignators && designator == 'M':
+			micros, ok = mulAddInt64(micros, n, microsPerMinute)
+		case designator == 'Y':
+			months, ok = mulAddInt64(months, n, 12)
+		case designator == 'M':
+			months, ok = mulAddInt64(months, n, 1)
+		case designator == 'W':
+			days, ok = mulAddInt64(days, n, 7)
+		case designator == 'D':
+			days, ok = mulAddInt64(days, n, 1)
+		}
+		if !ok {
+			return Interval{}, false
+		}
+	}
+
+	if components == 0 || months < math.MinInt32 || months > math.MaxInt32 || days < math.MinInt32 || days > math.MaxInt32 {
+		return Interval{}, false
+	}
+	if neg {
+		months, days, micros = -months, -days, -micros
+	}
+	return Interval{Months: int32(months), Days: int32(days), Micros: micros}, true
+}
+
+// parseIntervalSeconds parses a signed number of seconds with an optional fraction,
+// and returns it in microseconds.
+func parseIntervalSeconds(num string) (int64, bool) {
+	neg := false
+	if num != "" && (num[0] == '-' || num[0] == '+') {
+		neg = num[0] == '-'
+		num = num[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(strings.Replace(num, ",", ".", 1), ".")
+	isDigits := func(s string) bool {
+		return s != "" && !strings.ContainsFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	}
+	if !isDigits(intPart) || (hasFrac && !isDigits(frac)) {
+		return 0, false
+	}
+
+	secs, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	var fracMicros int64
+	if hasFrac {
+		frac = (frac + "000000")[:6]
+		if fracMicros, err = strconv.ParseInt(frac, 10, 64); err != nil {
+			return 0, false
+		}
+	}
+
+	micros, ok := mulAddInt64(fracMicros, secs, microsPerSecond)
+	if neg {
+		micros = -micros
+	}
+	return micros, ok
+}
+
+// mulAddInt64 returns acc + n * factor for a positive factor, and false on an overflow.
+func mulAddInt64(acc int64, n int64, factor int64) (int64, bool) {
+	if n > math.MaxInt64/factor || n < math.MinInt64/factor {
+		return 0, false
+	}
+	product := n * factor
+	if (product > 0 && acc > math.MaxInt64-product) || (product < 0 && acc < math.MinInt64-product) {
+		return 0, false
+	}
+	return acc + product, true
+}
@@ -0,0 +1,165 @@
+package duckdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterval(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	t.Run("INTERVAL binding", func(t *testing.T) {
+		interval := Interval{Days: 10, Months: 4, Micros: 4}
+		row := db.QueryRow("SELECT ?::INTERVAL", interval)
+
+		var res Interval
+		require.NoError(t, row.Scan(&res))
+		require.Equal(t, interval, res)
+	})
+
+	t.Run("INTERVAL scanning", func(t *testing.T) {
+		tests := map[string]struct {
+			input string
+			want  Interval
+		}{
+			"simple interval": {
+				input: "INTERVAL 5 HOUR",
+				want:  Interval{Days: 0, Months: 0, Micros: 18000000000},
+			},
+			"interval arithmetic": {
+				input: "INTERVAL 1 DAY + INTERVAL 5 DAY",
+				want:  Interval{Days: 6, Months: 0, Micros: 0},
+			},
+			"timestamp arithmetic": {
+				input: "CAST('2022-05-01' as TIMESTAMP) - CAST('2022-04-01' as TIMESTAMP)",
+				want:  Interval{Days: 30, Months: 0, Micros: 0},
+			},
+		}
+		for _, test := range tests {
+			var res Interval
+			err := db.QueryRow(fmt.Sprintf("SELECT %s", test.input)).Scan(&res)
+			require.NoError(t, err)
+			require.Equal(t, test.want, res)
+		}
+	})
+}
+
+func TestIntervalDuration(t *testing.T) {
+	i := NewIntervalFromDuration(36*time.Hour + 1500*time.Nanosecond)
+	require.Equal(t, Interval{Micros: 129600000001}, i)
+
+	d, err := Interval{Days: 2, Micros: -int64(time.Hour / time.Microsecond)}.Duration()
+	require.NoError(t, err)
+	require.Equal(t, 47*time.Hour, d)
+
+	_, err = Interval{Months: 1}.Duration()
+	testError(t, err, errIntervalMonths.Error())
+	_, err = Interval{Days: math.MaxInt32}.Duration()
+	testError(t, err, errIntervalOverflow.Error())
+}
+
+func TestIntervalAddTo(t *testing.T) {
+	ts := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+	i := Interval{Months: 1, Days: 1, Micros: int64(90 * time.Minute / time.Microsecond)}
+	require.Equal(t, time.Date(2024, time.March, 1, 13, 30, 0, 0, time.UTC), i.AddTo(ts))
+	require.Equal(t, time.Date(2023, time.February, 28, 12, 0, 0, 0, time.UTC), Interval{Months: -11}.AddTo(ts))
+
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	var expected time.Time
+	require.NoError(t, db.QueryRow(`SELECT ?::TIMESTAMP + ?::INTERVAL`, ts, i).Scan(&expected))
+	require.Equal(t, expected, i.AddTo(ts))
+}
+
+func TestIntervalNormalize(t *testing.T) {
+	i := Interval{Months: 1, Days: 45, Micros: 3*microsPerDay + 5}
+	require.Equal(t, Interval{Months: 2, Days: 18, Micros: 5}, i.Normalize())
+
+	i = Interval{Days: -31, Micros: -microsPerDay - 1}
+	require.Equal(t, Interval{Months: -1, Days: -2, Micros: -1}, i.Normalize())
+}
+
+func TestIntervalISO8601(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   Interval
+		output string
+	}{
+		{input: "P1M2DT3H", want: Interval{Months: 1, Days: 2, Micros: 3 * microsPerHour}, output: "P1M2DT3H"},
+		{input: "P1Y2M3DT4H5M6.5S", want: Interval{Months: 14, Days: 3, Micros: 4*microsPerHour + 5*microsPerMinute + 6500000}, output: "P1Y2M3DT4H5M6.5S"},
+		{input: "PT0S", want: Interval{}, output: "PT0S"},
+		{input: "p2w", want: Interval{Days: 14}, output: "P14D"},
+		{input: "PT90M", want: Interval{Micros: 90 * microsPerMinute}, output: "PT1H30M"},
+		{input: "PT0,000001S", want: Interval{Micros: 1}, output: "PT0.000001S"},
+		{input: "-P1Y1DT1.25S", want: Interval{Months: -12, Days: -1, Micros: -1250000}, output: "P-1Y-1DT-1.25S"},
+		{input: "P-1Y-1DT-1.25S", want: Interval{Months: -12, Days: -1, Micros: -1250000}, output: "P-1Y-1DT-1.25S"},
+		{input: "P1DT-2H", want: Interval{Days: 1, Micros: -2 * microsPerHour}, output: "P1DT-2H"},
+	}
+	for _, tc := range tests {
+		i, err := ParseInterval(tc.input)
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.want, i, tc.input)
+		require.Equal(t, tc.output, i.String(), tc.input)
+	}
+
+	for _, input := range []string{"", "P", "PT", "1D", "P1", "P1DT", "P1D2Y", "PT1H1H", "P1.5D", "PT1.S", "PT1.+5S", "P1S", "PT1D", "P1DT1HT1M", "P99999999999D"} {
+		_, err := ParseInterval(input)
+		testError(t, err, errIntervalSyntax.Error())
+	}
+}
+
+func TestIntervalMarshal(t *testing.T) {
+	i := Interval{Months: 1, Days: 2, Micros: 3 * microsPerHour}
+
+	text, err := i.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "P1M2DT3H", string(text))
+
+	var actual Interval
+	require.NoError(t, actual.UnmarshalText(text))
+	require.Equal(t, i, actual)
+	testError(t, actual.UnmarshalText([]byte("1 day")), errIntervalSyntax.Error())
+
+	// JSON keeps the object representation, but also accepts ISO 8601 durations.
+	data, err := json.Marshal(i)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"days":2,"months":1,"micros":10800000000}`, string(data))
+
+	actual = Interval{}
+	require.NoError(t, json.Unmarshal(data, &actual))
+	require.Equal(t, i, actual)
+
+	actual = Interval{}
+	require.NoError(t, json.Unmarshal([]byte(`"P1M2DT3H"`), &actual))
+	require.Equal(t, i, actual)
+
+	// Map keys use the text representation.
+	data, err = json.Marshal(map[Interval]int{i: 1})
+	require.NoError(t, err)
+	require.Equal(t, `{"P1M2DT3H":1}`, string(data))
+}
+
+func TestIntervalScanValue(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	var i Interval
+	require.NoError(t, db.QueryRow(`SELECT 'P1DT2H'`).Scan(&i))
+	require.Equal(t, Interval{Days: 1, Micros: 2 * microsPerHour}, i)
+
+	err := db.QueryRow(`SELECT 42`).Scan(&i)
+	require.ErrorContains(t, err, "invalid type `int32` for scanning `Interval`")
+
+	// A pointer is converted with the driver.Valuer interface.
+	retention := Interval{Months: -14, Days: 2, Micros: -3 * microsPerHour}
+	var actual Interval
+	require.NoError(t, db.QueryRow(`SELECT ?::INTERVAL`, &retention).Scan(&actual))
+	require.Equal(t, retention, actual)
+}
@@ -0,0 +1,231 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strings"
+)
+
+// Queryer executes queries returning rows. *sql.DB, *sql.Conn, and *sql.Tx implement it.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// QueryRows executes a query and returns an iterator over its rows, each decoded into a T.
+// If T is a struct (or a pointer to a struct), each column decodes into the field named by its `duckdb` tag,
+// or, without a tag, the field matching the column name case-insensitively. All columns must have a field.
+// Otherwise, e.g., for scalars, or a single STRUCT column, the query must return a single column,
+// which decodes directly into a T. See Composite for the decoding rules of nested values.
+// NULL values decode into the zero value.
+// The iteration stops after the first error.
+func QueryRows[T any](ctx context.Context, db Queryer, query string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		scan, err := newRowScanner[T](rows)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		for rows.Next() {
+			v, err := scan()
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+		if err = rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// QueryOne executes a query and decodes its first row into a T, see QueryRows.
+// It returns sql.ErrNoRows, if the query returns no rows.
+func QueryOne[T any](ctx context.Context, db Queryer, query string, args ...any) (T, error) {
+	for v, err := range QueryRows[T](ctx, db, query, args...) {
+		return v, err
+	}
+	var zero T
+	return zero, sql.ErrNoRows
+}
+
+// QueryChunks executes a query and returns an iterator over the data chunks of its result.
+// It avoids converting each value to a driver.Value, e.g., for column-at-a-time processing.
+// A chunk is only valid until the next iteration, and c cannot execute other queries during the iteration.
+// The iteration stops after the first error.
+func QueryChunks(ctx context.Context, c *sql.Conn, query string, args ...any) iter.Seq2[*DataChunk, error] {
+	return func(yield func(*DataChunk, error) bool) {
+		stopped := false
+		err := c.Raw(func(driverConn any) error {
+			conn, ok := driverConn.(*Conn)
+			if !ok {
+				return getError(errInvalidCon, nil)
+			}
+			nargs, err := conn.namedValues(args)
+			if err != nil {
+				return err
+			}
+			driverRows, err := conn.QueryContext(ctx, query, nargs)
+			if err != nil {
+				return err
+			}
+			r, ok := driverRows.(*rows)
+			if !ok {
+				return errors.Join(getError(errInternal, nil), driverRows.Close())
+			}
+
+			for {
+				if err = ctx.Err(); err != nil {
+					break
+				}
+				if err = r.nextChunk(); err != nil {
+					if errors.Is(err, io.EOF) {
+						err = nil
+					}
+					break
+				}
+				if !yield(&r.chunk, nil) {
+					stopped = true
+					break
+				}
+			}
+			return errors.Join(err, r.Close())
+		})
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+// namedValues converts args to named values, like database/sql does before passing them to the driver.
+func (conn *Conn) namedValues(args []any) ([]driver.NamedValue, error) {
+	nargs := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		nv := driver.NamedValue{Ordinal: i + 1, Value: arg}
+		if named, ok := arg.(sql.NamedArg); ok {
+			nv.Name, nv.Value = named.Name, named.Value
+		}
+
+		err := conn.CheckNamedValue(&nv)
+		if errors.Is(err, driver.ErrSkip) {
+			nv.Value, err = driver.DefaultParameterConverter.ConvertValue(nv.Value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("converting argument $%d: %w", nv.Ordinal, err)
+		}
+		nargs[i] = nv
+	}
+	return nargs, nil
+}
+
+// newRowScanner returns a function scanning the current row of rows into a T.
+func newRowScanner[T any](rows *sql.Rows) (func() (T, error), error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]any, len(columnTypes))
+	dest := make([]any, len(columnTypes))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	t := reflect.TypeFor[T]()
+	structType := t
+	if t.Kind() == reflect.Pointer {
+		structType = t.Elem()
+	}
+
+	if !mapsColumnsToFields(structType, columnTypes) {
+		if len(columnTypes) != 1 {
+			return nil, getError(errAPI, fmt.Errorf("cannot decode into `%s`: %w", t, columnCountError(len(columnTypes), 1)))
+		}
+		decode := decoderFor(t)
+		name := columnTypes[0].Name()
+		return func() (T, error) {
+			var v T
+			if err := rows.Scan(dest...); err != nil {
+				return v, err
+			}
+			err := decode(values[0], reflect.ValueOf(&v).Elem(), name)
+			return v, err
+		}, nil
+	}
+
+	// Map each column to a struct field.
+	fields := structFields(structType)
+	columnFields := make([]structField, len(columnTypes))
+	for i, columnType := range columnTypes {
+		name := columnType.Name()
+		idx := -1
+		for j := range fields {
+			if fields[j].name == name {
+				idx = j
+				break
+			}
+			if idx == -1 && strings.EqualFold(fields[j].name, name) {
+				idx = j
+			}
+		}
+		if idx == -1 {
+			return nil, getError(errAPI, fmt.Errorf("no field of `%s` matches the column %q", structType, name))
+		}
+		columnFields[i] = fields[idx]
+		columnFields[i].decode = decoderFor(structType.FieldByIndex(fields[idx].index).Type)
+	}
+
+	return func() (T, error) {
+		var v T
+		if err := rows.Scan(dest...); err != nil {
+			return v, err
+		}
+
+		target := reflect.ValueOf(&v).Elem()
+		if t.Kind() == reflect.Pointer {
+			target.Set(reflect.New(structType))
+			target = target.Elem()
+		}
+		for i := range columnFields {
+			field := &columnFields[i]
+			fieldValue, err := fieldByIndexAlloc(target, field.index)
+			if err != nil {
+				return v, decodeError(field.goName, err)
+			}
+			if err = field.decode(values[i], fieldValue, field.goName); err != nil {
+				return v, err
+			}
+		}
+		return v, nil
+	}, nil
+}
+
+// mapsColumnsToFields returns true, if the columns map to the fields of t.
+// Otherwise, a single column decodes directly into t.
+func mapsColumnsToFields(t reflect.Type, columnTypes []*sql.ColumnType) bool {
+	if t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(scannerType) {
+		return false
+	}
+	if len(columnTypes) != 1 {
+		return true
+	}
+
+	// A single column decodes directly into t, if it is a STRUCT, or if its values are a t, e.g., a time.Time.
+	scanType := columnTypes[0].ScanType()
+	if scanType == nil {
+		return true
+	}
+	return scanType != reflect.TypeFor[map[string]any]() && !scanType.AssignableTo(t)
+}
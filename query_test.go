@@ -0,0 +1,216 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type queryUser struct {
+	ID      int64 `duckdb:"user_id"`
+	Name    string
+	Email   *string
+	Tags    []string
+	Created time.Time
+}
+
+type queryPoint struct {
+	X int32
+	Y int32
+}
+
+func TestQueryRows(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	_, err := db.Exec(`CREATE TABLE users (user_id BIGINT, name VARCHAR, email VARCHAR, tags VARCHAR[], created TIMESTAMP)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO users VALUES
+		(1, 'ada', 'ada@example.com', ['admin'], TIMESTAMP '2024-01-01 00:00:00'),
+		(2, 'bob', NULL, NULL, TIMESTAMP '2024-02-01 00:00:00')`)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	email := "ada@example.com"
+	expected := []queryUser{
+		{ID: 1, Name: "ada", Email: &email, Tags: []string{"admin"}, Created: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, Name: "bob", Created: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		var users []queryUser
+		for u, err := range QueryRows[queryUser](ctx, db, `SELECT * FROM users ORDER BY user_id`) {
+			require.NoError(t, err)
+			users = append(users, u)
+		}
+		require.Equal(t, expected, users)
+	})
+
+	t.Run("struct pointer", func(t *testing.T) {
+		var users []*queryUser
+		for u, err := range QueryRows[*queryUser](ctx, db, `SELECT user_id, name FROM users WHERE user_id = ?`, 2) {
+			require.NoError(t, err)
+			users = append(users, u)
+		}
+		require.Equal(t, []*queryUser{{ID: 2, Name: "bob"}}, users)
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		var names []string
+		for name, err := range QueryRows[string](ctx, db, `SELECT name FROM users ORDER BY user_id`) {
+			require.NoError(t, err)
+			names = append(names, name)
+		}
+		require.Equal(t, []string{"ada", "bob"}, names)
+
+		var ids []int
+		for id, err := range QueryRows[int](ctx, db, `SELECT user_id FROM users ORDER BY user_id`) {
+			require.NoError(t, err)
+			ids = append(ids, id)
+		}
+		require.Equal(t, []int{1, 2}, ids)
+	})
+
+	t.Run("single STRUCT column", func(t *testing.T) {
+		var points []queryPoint
+		for p, err := range QueryRows[queryPoint](ctx, db, `SELECT {'x': i, 'y': i * 2} FROM range(3) t(i)`) {
+			require.NoError(t, err)
+			points = append(points, p)
+		}
+		require.Equal(t, []queryPoint{{0, 0}, {1, 2}, {2, 4}}, points)
+	})
+
+	t.Run("early break", func(t *testing.T) {
+		n := 0
+		for _, err := range QueryRows[int64](ctx, db, `SELECT * FROM range(10000)`) {
+			require.NoError(t, err)
+			n++
+			if n == 3 {
+				break
+			}
+		}
+		require.Equal(t, 3, n)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, err := range QueryRows[queryUser](ctx, db, `SELECT 1 AS unknown`) {
+			testError(t, err, errAPI.Error(), `no field of `+"`duckdb.queryUser`"+` matches the column "unknown"`)
+		}
+		for _, err := range QueryRows[int](ctx, db, `SELECT 1, 2`) {
+			testError(t, err, errAPI.Error(), columnCountErrMsg)
+		}
+		for _, err := range QueryRows[int](ctx, db, `SELECT * FROM does_not_exist`) {
+			require.ErrorContains(t, err, "does_not_exist")
+		}
+
+		var n int
+		for _, err := range QueryRows[queryUser](ctx, db, `SELECT 'x' AS user_id FROM range(3)`) {
+			require.ErrorContains(t, err, "ID: "+castErrMsg)
+			n++
+		}
+		require.Equal(t, 1, n)
+	})
+}
+
+func TestQueryOne(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	ctx := context.Background()
+	conn := openConnWrapper(t, db, ctx)
+	defer closeConnWrapper(t, conn)
+
+	count, err := QueryOne[int64](ctx, conn, `SELECT count(*) FROM range(?)`, 5)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), count)
+
+	d, err := QueryOne[Decimal](ctx, conn, `SELECT 1.5::DECIMAL(4, 1)`)
+	require.NoError(t, err)
+	require.Equal(t, Decimal{Width: 4, Scale: 1, Value: big.NewInt(15)}, d)
+
+	ts, err := QueryOne[time.Time](ctx, conn, `SELECT TIMESTAMP '2024-01-01 00:00:00'`)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ts)
+
+	p, err := QueryOne[queryPoint](ctx, conn, `SELECT 1 AS x, 2 AS y`)
+	require.NoError(t, err)
+	require.Equal(t, queryPoint{1, 2}, p)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, tx.Rollback()) }()
+
+	_, err = QueryOne[int](ctx, tx, `SELECT 1 WHERE false`)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestQueryChunks(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	ctx := context.Background()
+	conn := openConnWrapper(t, db, ctx)
+	defer closeConnWrapper(t, conn)
+
+	t.Run("all chunks", func(t *testing.T) {
+		rowCount, sum, chunkCount := 0, int64(0), 0
+		for chunk, err := range QueryChunks(ctx, conn, `SELECT i, i::VARCHAR FROM range(?) t(i)`, 5000) {
+			require.NoError(t, err)
+			require.LessOrEqual(t, chunk.GetSize(), GetDataChunkCapacity())
+			for row := 0; row < chunk.GetSize(); row++ {
+				v, err := chunk.GetValue(0, row)
+				require.NoError(t, err)
+				sum += v.(int64)
+			}
+			rowCount += chunk.GetSize()
+			chunkCount++
+		}
+		require.Equal(t, 5000, rowCount)
+		require.Equal(t, int64(4999*5000/2), sum)
+		require.Greater(t, chunkCount, 1)
+	})
+
+	t.Run("named args", func(t *testing.T) {
+		for chunk, err := range QueryChunks(ctx, conn, `SELECT $name::VARCHAR`, sql.Named("name", "duck")) {
+			require.NoError(t, err)
+			v, err := chunk.GetValue(0, 0)
+			require.NoError(t, err)
+			require.Equal(t, "duck", v)
+		}
+	})
+
+	t.Run("early break", func(t *testing.T) {
+		n := 0
+		for _, err := range QueryChunks(ctx, conn, `SELECT * FROM range(10000)`) {
+			require.NoError(t, err)
+			n++
+			break
+		}
+		require.Equal(t, 1, n)
+
+		// The connection remains usable.
+		var count int
+		require.NoError(t, conn.QueryRowContext(ctx, `SELECT count(*) FROM range(3)`).Scan(&count))
+		require.Equal(t, 3, count)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		n := 0
+		for chunk, err := range QueryChunks(ctx, conn, `SELECT * FROM does_not_exist`) {
+			require.Nil(t, chunk)
+			require.ErrorContains(t, err, "does_not_exist")
+			n++
+		}
+		require.Equal(t, 1, n)
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		for _, err := range QueryChunks(cancelCtx, conn, `SELECT 42`) {
+			require.ErrorIs(t, err, context.Canceled)
+		}
+	})
+}
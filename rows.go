@@ -55,21 +55,9 @@ func (r *rows) Columns() []string {
 
 func (r *rows) Next(dst []driver.Value) error {
 	for r.rowCount == r.chunk.size {
-		if r.closeChunk {
-			r.chunk.close()
-			r.closeChunk = false
-		}
-		if r.chunkIdx == r.chunkCount {
-			return io.EOF
-		}
-		chunk := mapping.ResultGetChunk(r.res, r.chunkIdx)
-		r.closeChunk = true
-		if err := r.chunk.initFromDuckDataChunk(chunk, false); err != nil {
-			return getError(err, nil)
+		if err := r.nextChunk(); err != nil {
+			return err
 		}
-
-		r.chunkIdx++
-		r.rowCount = 0
 	}
 
 	columnCount := len(r.chunk.columns)
@@ -84,6 +72,45 @@ func (r *rows) Next(dst []driver.Value) error {
 	return nil
 }
 
+// nextChunk closes the active data chunk and fetches the next chunk of the result.
+// It returns io.EOF after the last chunk.
+func (r *rows) nextChunk() error {
+	if r.closeChunk {
+		r.chunk.close()
+		r.closeChunk = false
+	}
+	if r.chunkIdx == r.chunkCount {
+		return io.EOF
+	}
+	chunk := mapping.ResultGetChunk(r.res, r.chunkIdx)
+	r.closeChunk = true
+	if err := r.chunk.initFromDuckDataChunk(chunk, false); err != nil {
+		return getError(err, nil)
+	}
+	if err := r.setTimestampTZLocation(); err != nil {
+		return err
+	}
+	if r.stmt != nil && r.stmt.conn.orderedMaps {
+		r.chunk.setOrderedMaps()
+	}
+
+	r.chunkIdx++
+	r.rowCount = 0
+	return nil
+}
+
+func (r *rows) setTimestampTZLocation() error {
+	if r.stmt == nil || !r.chunk.hasTimestampTZ() {
+		return nil
+	}
+	loc, err := r.stmt.conn.timestampTZLocation()
+	if err != nil {
+		return err
+	}
+	r.chunk.setTimestampTZLocation(loc)
+	return nil
+}
+
 // ColumnTypeScanType implements driver.RowsColumnTypeScanType.
 func (r *rows) ColumnTypeScanType(index int) reflect.Type {
 	logicalType := mapping.ColumnLogicalType(&r.res, mapping.IdxT(index))
@@ -138,6 +165,9 @@ func (r *rows) ColumnTypeScanType(index int) reflect.Type {
 	case TYPE_STRUCT:
 		return reflect.TypeOf(map[string]any{})
 	case TYPE_MAP:
+		if (r.stmt != nil && r.stmt.conn.orderedMaps) || !mapKeyIsComparable(logicalType) {
+			return reflect.TypeOf(OrderedMap{})
+		}
 		return reflect.TypeOf(Map{})
 	case TYPE_ARRAY:
 		return reflect.TypeOf([]any{})
@@ -59,6 +59,13 @@ type ScalarFunc interface {
 	Executor() ScalarFuncExecutor
 }
 
+// chunkScalarFunc is a ScalarFunc, which executes for all rows of the input chunk at once,
+// e.g., an Arrow-backed scalar function. It handles NULL values itself.
+type chunkScalarFunc interface {
+	ScalarFunc
+	executeChunk(input *DataChunk, output *vector) error
+}
+
 // RegisterScalarUDF registers a user-defined scalar function.
 // *sql.Conn is the SQL connection on which to register the scalar function.
 // name is the function name, and f is the scalar function's interface ScalarFunc.
@@ -143,6 +150,13 @@ func scalar_udf_callback(functionInfoPtr unsafe.Pointer, inputPtr unsafe.Pointer
 		return
 	}
 
+	if f, ok := function.(chunkScalarFunc); ok {
+		if err := f.executeChunk(&inputChunk, &outputChunk.columns[0]); err != nil {
+			mapping.ScalarFunctionSetError(functionInfo, getError(errAPI, err).Error())
+		}
+		return
+	}
+
 	executor := function.Executor()
 	nullInNullOut := !function.Config().SpecialNullHandling
 	values := make([]driver.Value, len(inputChunk.columns))
@@ -245,7 +259,7 @@ func createScalarFunc(name string, f ScalarFunc) (mapping.ScalarFunction, error)
 	if f == nil {
 		return mapping.ScalarFunction{}, errScalarUDFIsNil
 	}
-	if f.Executor().RowExecutor == nil {
+	if _, ok := f.(chunkScalarFunc); !ok && f.Executor().RowExecutor == nil {
 		return mapping.ScalarFunction{}, errScalarUDFNoExecutor
 	}
 
@@ -0,0 +1,323 @@
+//go:build duckdb_arrow
+
+package duckdb
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/marcboeker/go-duckdb/mapping"
+)
+
+// ArrowScalarFuncExecutor executes a user-defined scalar function on a batch of rows.
+// The input record has a column for each input parameter, named arg0, arg1, etc.
+// It returns an array with a result for each row of the input record.
+// The input record is only valid during the call, and the executor must not release it.
+// The returned array is released after its values have been copied, so the executor must retain it,
+// if it returns a column of the input record.
+type ArrowScalarFuncExecutor func(input arrow.Record) (arrow.Array, error)
+
+// NewArrowScalarFunc returns a ScalarFunc, which executes the Apache Arrow executor fn on each data chunk,
+// instead of executing a function for each row. Register it with RegisterScalarUDF or RegisterScalarUDFSet.
+// The type of the returned array must be convertible to the ResultTypeInfo, like the field types of
+// Appender.AppendRecord. With the default NULL handling, the result of a row with a NULL input value is NULL,
+// regardless of the returned array.
+func NewArrowScalarFunc(config ScalarFuncConfig, fn ArrowScalarFuncExecutor) (ScalarFunc, error) {
+	if fn == nil {
+		return nil, getError(errAPI, errScalarUDFNoExecutor)
+	}
+	return &arrowScalarFunc{config: config, fn: fn}, nil
+}
+
+type arrowScalarFunc struct {
+	config ScalarFuncConfig
+	fn     ArrowScalarFuncExecutor
+}
+
+func (f *arrowScalarFunc) Config() ScalarFuncConfig {
+	return f.config
+}
+
+// Executor returns an empty ScalarFuncExecutor, as the function executes on data chunks.
+func (f *arrowScalarFunc) Executor() ScalarFuncExecutor {
+	return ScalarFuncExecutor{}
+}
+
+func (f *arrowScalarFunc) executeChunk(input *DataChunk, output *vector) error {
+	rowCount := input.GetSize()
+	rec, err := dataChunkToRecord(input, rowCount)
+	if err != nil {
+		return err
+	}
+	arr, err := f.fn(rec)
+	rec.Release()
+	if err != nil {
+		return err
+	}
+	if arr == nil {
+		return interfaceIsNilError("arrow.Array")
+	}
+	defer arr.Release()
+
+	if arr.Len() != rowCount {
+		return invalidInputError(fmt.Sprintf("%d results", arr.Len()), fmt.Sprintf("%d results", rowCount))
+	}
+	if err = checkArrowType(arr.DataType(), output); err != nil {
+		return err
+	}
+	if err = setArrowColumn(output, arr, 0, 0, rowCount); err != nil {
+		return err
+	}
+
+	if f.config.SpecialNullHandling {
+		return nil
+	}
+	for colIdx := range input.columns {
+		column := &input.columns[colIdx]
+		for rowIdx := 0; rowIdx < rowCount; rowIdx++ {
+			if column.getNull(mapping.IdxT(rowIdx)) {
+				output.setNull(mapping.IdxT(rowIdx))
+			}
+		}
+	}
+	return nil
+}
+
+// dataChunkToRecord copies the first rowCount rows of the chunk into an Apache Arrow record.
+func dataChunkToRecord(chunk *DataChunk, rowCount int) (arrow.Record, error) {
+	fields := make([]arrow.Field, len(chunk.columns))
+	columns := make([]arrow.Array, 0, len(chunk.columns))
+	defer func() {
+		for _, arr := range columns {
+			arr.Release()
+		}
+	}()
+
+	for colIdx := range chunk.columns {
+		vec := &chunk.columns[colIdx]
+		dt, err := vectorArrowType(vec)
+		if err != nil {
+			return nil, addIndexToError(err, colIdx)
+		}
+		fields[colIdx] = arrow.Field{Name: fmt.Sprintf("arg%d", colIdx), Type: dt, Nullable: true}
+
+		b := array.NewBuilder(memory.DefaultAllocator, dt)
+		appendArrowColumn(b, vec, rowCount)
+		columns = append(columns, b.NewArray())
+		b.Release()
+	}
+	return array.NewRecord(arrow.NewSchema(fields, nil), columns, int64(rowCount)), nil
+}
+
+// vectorArrowType returns the Apache Arrow type of the vector's values.
+func vectorArrowType(vec *vector) (arrow.DataType, error) {
+	if vec.json {
+		return arrow.BinaryTypes.String, nil
+	}
+
+	switch vec.Type {
+	case TYPE_BOOLEAN:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case TYPE_TINYINT:
+		return arrow.PrimitiveTypes.Int8, nil
+	case TYPE_SMALLINT:
+		return arrow.PrimitiveTypes.Int16, nil
+	case TYPE_INTEGER:
+		return arrow.PrimitiveTypes.Int32, nil
+	case TYPE_BIGINT:
+		return arrow.PrimitiveTypes.Int64, nil
+	case TYPE_UTINYINT:
+		return arrow.PrimitiveTypes.Uint8, nil
+	case TYPE_USMALLINT:
+		return arrow.PrimitiveTypes.Uint16, nil
+	case TYPE_UINTEGER:
+		return arrow.PrimitiveTypes.Uint32, nil
+	case TYPE_UBIGINT:
+		return arrow.PrimitiveTypes.Uint64, nil
+	case TYPE_FLOAT:
+		return arrow.PrimitiveTypes.Float32, nil
+	case TYPE_DOUBLE:
+		return arrow.PrimitiveTypes.Float64, nil
+	case TYPE_VARCHAR, TYPE_ENUM:
+		return arrow.BinaryTypes.String, nil
+	case TYPE_BLOB:
+		return arrow.BinaryTypes.Binary, nil
+	case TYPE_DATE:
+		return arrow.FixedWidthTypes.Date32, nil
+	case TYPE_TIMESTAMP:
+		return &arrow.TimestampType{Unit: arrow.Microsecond}, nil
+	case TYPE_TIMESTAMP_S:
+		return &arrow.TimestampType{Unit: arrow.Second}, nil
+	case TYPE_TIMESTAMP_MS:
+		return &arrow.TimestampType{Unit: arrow.Millisecond}, nil
+	case TYPE_TIMESTAMP_NS:
+		return &arrow.TimestampType{Unit: arrow.Nanosecond}, nil
+	case TYPE_TIMESTAMP_TZ:
+		return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, nil
+	case TYPE_TIME:
+		return arrow.FixedWidthTypes.Time64us, nil
+	case TYPE_INTERVAL:
+		return arrow.FixedWidthTypes.MonthDayNanoInterval, nil
+	case TYPE_DECIMAL:
+		return &arrow.Decimal128Type{Precision: int32(vec.decimalWidth), Scale: int32(vec.decimalScale)}, nil
+	case TYPE_LIST, TYPE_ARRAY:
+		child, err := vectorArrowType(&vec.childVectors[0])
+		if err != nil {
+			return nil, err
+		}
+		if vec.Type == TYPE_ARRAY {
+			return arrow.FixedSizeListOf(int32(vec.arrayLength), child), nil
+		}
+		return arrow.ListOf(child), nil
+	case TYPE_MAP:
+		entries := &vec.childVectors[0]
+		key, err := vectorArrowType(&entries.childVectors[0])
+		if err != nil {
+			return nil, err
+		}
+		value, err := vectorArrowType(&entries.childVectors[1])
+		if err != nil {
+			return nil, err
+		}
+		return arrow.MapOf(key, value), nil
+	case TYPE_STRUCT:
+		fields := make([]arrow.Field, len(vec.childVectors))
+		for i := range vec.childVectors {
+			dt, err := vectorArrowType(&vec.childVectors[i])
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = arrow.Field{Name: vec.structEntries[i].Name(), Type: dt, Nullable: true}
+		}
+		return arrow.StructOf(fields...), nil
+	}
+	return nil, unsupportedTypeError(typeToStringMap[vec.Type])
+}
+
+// appendArrowColumn appends the first rowCount rows of the vector to the builder of its Apache Arrow type.
+func appendArrowColumn(b array.Builder, vec *vector, rowCount int) {
+	// Copy primitive values without conversion.
+	switch b := b.(type) {
+	case *array.Int8Builder:
+		appendArrowPrimitives(b, vec, rowCount)
+	case *array.Int16Builder:
+		appendArrowPrimitives(b, vec, rowCount)
+	case *array.Int32Builder:
+		appendArrowPrimitives(b, vec, rowCount)
+	case *array.Int64Builder:
+		appendArrowPrimitives(b, vec, rowCount)
+	case *array.Uint8Builder:
+		appendArrowPrimitives(b, vec, rowCount)
+	case *array.Uint16Builder:
+		appendArrowPrimitives(b, vec, rowCount)
+	case *array.Uint32Builder:
+		appendArrowPrimitives(b, vec, rowCount)
+	case *array.Uint64Builder:
+		appendArrowPrimitives(b, vec, rowCount)
+	case *array.Float32Builder:
+		appendArrowPrimitives(b, vec, rowCount)
+	case *array.Float64Builder:
+		appendArrowPrimitives(b, vec, rowCount)
+	default:
+		for rowIdx := 0; rowIdx < rowCount; rowIdx++ {
+			appendArrowValue(b, vec, mapping.IdxT(rowIdx))
+		}
+	}
+}
+
+func appendArrowPrimitives[T fixedWidthType](b interface{ AppendValues([]T, []bool) }, vec *vector, rowCount int) {
+	values := unsafe.Slice((*T)(vec.dataPtr), rowCount)
+	var valid []bool
+	if vec.maskPtr != nil {
+		valid = make([]bool, rowCount)
+		for i := range valid {
+			valid[i] = !vec.getNull(mapping.IdxT(i))
+		}
+	}
+	b.AppendValues(values, valid)
+}
+
+// appendArrowValue appends a value of the vector to the builder of its Apache Arrow type.
+func appendArrowValue(b array.Builder, vec *vector, rowIdx mapping.IdxT) {
+	if vec.getNull(rowIdx) {
+		b.AppendNull()
+		return
+	}
+
+	switch b := b.(type) {
+	case *array.BooleanBuilder:
+		b.Append(getPrimitive[bool](vec, rowIdx))
+	case *array.Int8Builder:
+		b.Append(getPrimitive[int8](vec, rowIdx))
+	case *array.Int16Builder:
+		b.Append(getPrimitive[int16](vec, rowIdx))
+	case *array.Int32Builder:
+		b.Append(getPrimitive[int32](vec, rowIdx))
+	case *array.Int64Builder:
+		b.Append(getPrimitive[int64](vec, rowIdx))
+	case *array.Uint8Builder:
+		b.Append(getPrimitive[uint8](vec, rowIdx))
+	case *array.Uint16Builder:
+		b.Append(getPrimitive[uint16](vec, rowIdx))
+	case *array.Uint32Builder:
+		b.Append(getPrimitive[uint32](vec, rowIdx))
+	case *array.Uint64Builder:
+		b.Append(getPrimitive[uint64](vec, rowIdx))
+	case *array.Float32Builder:
+		b.Append(getPrimitive[float32](vec, rowIdx))
+	case *array.Float64Builder:
+		b.Append(getPrimitive[float64](vec, rowIdx))
+	case *array.StringBuilder:
+		if vec.Type == TYPE_ENUM {
+			b.Append(vec.getEnum(rowIdx))
+		} else {
+			b.Append(vec.getBytes(rowIdx).(string))
+		}
+	case *array.BinaryBuilder:
+		b.Append(vec.getBytes(rowIdx).([]byte))
+	case *array.Date32Builder:
+		b.Append(arrow.Date32(getPrimitive[int32](vec, rowIdx)))
+	case *array.TimestampBuilder:
+		// All TIMESTAMP types are 64-bit integers in their unit.
+		b.Append(arrow.Timestamp(getPrimitive[int64](vec, rowIdx)))
+	case *array.Time64Builder:
+		b.Append(arrow.Time64(getPrimitive[int64](vec, rowIdx)))
+	case *array.MonthDayNanoIntervalBuilder:
+		interval := vec.getInterval(rowIdx)
+		b.Append(arrow.MonthDayNanoInterval{Months: interval.Months, Days: interval.Days, Nanoseconds: interval.Micros * 1000})
+	case *array.Decimal128Builder:
+		b.Append(decimal128.FromBigInt(vec.getDecimal(rowIdx).Value))
+	case *array.MapBuilder:
+		entry := getPrimitive[mapping.ListEntry](vec, rowIdx)
+		offset, length := mapping.ListEntryMembers(&entry)
+		entries := &vec.childVectors[0]
+		b.Append(true)
+		for i := offset; i < offset+length; i++ {
+			appendArrowValue(b.KeyBuilder(), &entries.childVectors[0], mapping.IdxT(i))
+			appendArrowValue(b.ItemBuilder(), &entries.childVectors[1], mapping.IdxT(i))
+		}
+	case *array.ListBuilder:
+		entry := getPrimitive[mapping.ListEntry](vec, rowIdx)
+		offset, length := mapping.ListEntryMembers(&entry)
+		b.Append(true)
+		for i := offset; i < offset+length; i++ {
+			appendArrowValue(b.ValueBuilder(), &vec.childVectors[0], mapping.IdxT(i))
+		}
+	case *array.FixedSizeListBuilder:
+		offset := rowIdx * vec.arrayLength
+		b.Append(true)
+		for i := offset; i < offset+vec.arrayLength; i++ {
+			appendArrowValue(b.ValueBuilder(), &vec.childVectors[0], i)
+		}
+	case *array.StructBuilder:
+		b.Append(true)
+		for i := range vec.childVectors {
+			appendArrowValue(b.FieldBuilder(i), &vec.childVectors[i], rowIdx)
+		}
+	}
+}
@@ -21,6 +21,7 @@ type (
 	variadicSUDF      struct{}
 	anyTypeSUDF       struct{}
 	unionTestSUDF     struct{}
+	reverseMapSUDF    struct{}
 	errExecutorSUDF   struct{}
 	errInputNilSUDF   struct{}
 	errResultNilSUDF  struct{}
@@ -184,6 +185,26 @@ func (*unionTestSUDF) Executor() ScalarFuncExecutor {
 	}
 }
 
+func (*reverseMapSUDF) Config() ScalarFuncConfig {
+	return ScalarFuncConfig{
+		InputTypeInfos: []TypeInfo{currentInfo},
+		ResultTypeInfo: currentInfo,
+	}
+}
+
+func (*reverseMapSUDF) Executor() ScalarFuncExecutor {
+	return ScalarFuncExecutor{
+		RowExecutor: func(values []driver.Value) (any, error) {
+			m := values[0].(OrderedMap)
+			reversed := make(OrderedMap, 0, len(m))
+			for i := len(m) - 1; i >= 0; i-- {
+				reversed = append(reversed, m[i])
+			}
+			return reversed, nil
+		},
+	}
+}
+
 func TestSimpleScalarUDF(t *testing.T) {
 	db := openDbWrapper(t, ``)
 	defer closeDbWrapper(t, db)
@@ -419,6 +440,35 @@ func TestUnionScalarUDF(t *testing.T) {
 	require.Equal(t, nil, res)
 }
 
+func TestOrderedMapScalarUDF(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	conn := openConnWrapper(t, db, context.Background())
+	defer closeConnWrapper(t, conn)
+
+	intInfo, err := NewTypeInfo(TYPE_INTEGER)
+	require.NoError(t, err)
+	listInfo, err := NewListInfo(intInfo)
+	require.NoError(t, err)
+	varcharInfo, err := NewTypeInfo(TYPE_VARCHAR)
+	require.NoError(t, err)
+	currentInfo, err = NewMapInfo(listInfo, varcharInfo)
+	require.NoError(t, err)
+
+	var udf *reverseMapSUDF
+	err = RegisterScalarUDF(conn, "reverse_map", udf)
+	require.NoError(t, err)
+
+	var res OrderedMap
+	row := db.QueryRow(`SELECT reverse_map(MAP([[1], [2, 3]], ['a', 'b'])) AS res`)
+	require.NoError(t, row.Scan(&res))
+	require.Equal(t, OrderedMap{
+		{Key: []any{int32(2), int32(3)}, Value: "b"},
+		{Key: []any{int32(1)}, Value: "a"},
+	}, res)
+}
+
 func TestErrScalarUDF(t *testing.T) {
 	db := openDbWrapper(t, ``)
 	defer closeDbWrapper(t, db)
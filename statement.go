@@ -166,15 +166,42 @@ func (s *Stmt) bindHugeint(val *big.Int, n int) (mapping.State, error) {
 	return state, nil
 }
 
+func (s *Stmt) bindDecimal(val Decimal, n int) (mapping.State, error) {
+	// Validate the width and scale, as DuckDB expects the value to fit.
+	val, err := val.Rescale(val.Width, val.Scale)
+	if err != nil {
+		return mapping.StateError, addIndexToError(err, n+1)
+	}
+	hugeint, err := hugeIntFromNative(val.Value)
+	if err != nil {
+		return mapping.StateError, err
+	}
+	state := mapping.BindDecimal(*s.preparedStmt, mapping.IdxT(n+1), *mapping.NewDecimal(val.Width, val.Scale, *hugeint))
+	return state, nil
+}
+
+// bindTimestamp binds a time.Time. A TIMESTAMPTZ parameter gets its instant, while the other TIMESTAMP
+// parameters have no time zone, so they get its wall clock in its location, e.g., 12:00 for 12:00 in Europe/Berlin.
 func (s *Stmt) bindTimestamp(val driver.NamedValue, t Type, n int) (mapping.State, error) {
+	if ti, ok := val.Value.(time.Time); ok && t != TYPE_TIMESTAMP_TZ {
+		val.Value = time.Date(ti.Year(), ti.Month(), ti.Day(), ti.Hour(), ti.Minute(), ti.Second(), ti.Nanosecond(), time.UTC)
+	}
+
 	var state mapping.State
 	switch t {
-	case TYPE_TIMESTAMP, TYPE_TIMESTAMP_TZ:
+	case TYPE_TIMESTAMP:
 		v, err := getMappedTimestamp(val.Value)
 		if err != nil {
 			return mapping.StateError, err
 		}
 		state = mapping.BindTimestamp(*s.preparedStmt, mapping.IdxT(n+1), *v)
+	case TYPE_TIMESTAMP_TZ:
+		// Bind the instant, as binding a TIMESTAMP casts it with the TimeZone setting.
+		v, err := getMappedTimestamp(val.Value)
+		if err != nil {
+			return mapping.StateError, err
+		}
+		state = mapping.BindTimestampTZ(*s.preparedStmt, mapping.IdxT(n+1), *v)
 	case TYPE_TIMESTAMP_S:
 		v, err := getMappedTimestampS(val.Value)
 		if err != nil {
@@ -242,7 +269,7 @@ func (s *Stmt) bindJSON(val driver.NamedValue, n int) (mapping.State, error) {
 	return mapping.StateError, addIndexToError(unsupportedTypeError("JSON interface, need []byte or string"), n+1)
 }
 
-// Used for binding Array, List, Struct. In the future, also Map and Union
+// Used for binding Array, List, Struct, Map. In the future, also Union
 func (s *Stmt) bindCompositeValue(val driver.NamedValue, n int) (mapping.State, error) {
 	lt, err := s.paramLogicalType(n + 1)
 	defer mapping.DestroyLogicalType(&lt)
@@ -284,9 +311,9 @@ func (s *Stmt) bindComplexValue(val driver.NamedValue, n int, t Type, name strin
 		return s.bindDate(val, n)
 	case TYPE_TIME, TYPE_TIME_TZ:
 		return s.bindTime(val, t, n)
-	case TYPE_ARRAY, TYPE_LIST, TYPE_STRUCT:
+	case TYPE_ARRAY, TYPE_LIST, TYPE_STRUCT, TYPE_MAP:
 		return s.bindCompositeValue(val, n)
-	case TYPE_MAP, TYPE_ENUM, TYPE_UNION:
+	case TYPE_ENUM, TYPE_UNION:
 		// FIXME: for other types: duckdb_param_logical_type once available, then create duckdb_value + duckdb_bind_value
 		// FIXME: for other types: use NamedValueChecker to support.
 		return mapping.StateError, addIndexToError(unsupportedTypeError(name), n+1)
@@ -337,9 +364,7 @@ func (s *Stmt) bindValue(val driver.NamedValue, n int) (mapping.State, error) {
 	case *big.Int:
 		return s.bindHugeint(v, n)
 	case Decimal:
-		// FIXME: use NamedValueChecker to support this type.
-		name := typeToStringMap[TYPE_DECIMAL]
-		return mapping.StateError, addIndexToError(unsupportedTypeError(name), n+1)
+		return s.bindDecimal(v, n)
 	case uint8:
 		return mapping.BindUInt8(*s.preparedStmt, mapping.IdxT(n+1), v), nil
 	case uint16:
@@ -527,6 +552,7 @@ func (s *Stmt) executeBound(ctx context.Context) (*mapping.Result, error) {
 	// sometimes the bg goroutine is not scheduled immediately and by that time if another query is running on this connection
 	// it can cancel that query so need to wait for it to finish as well
 	<-bgDoneCh
+	s.invalidateSessionLocation()
 	if state == mapping.StateError {
 		if ctx.Err() != nil {
 			mapping.DestroyResult(&res)
@@ -540,6 +566,13 @@ func (s *Stmt) executeBound(ctx context.Context) (*mapping.Result, error) {
 	return &res, nil
 }
 
+// invalidateSessionLocation invalidates the cached TimeZone setting, as a SET statement might have changed it.
+func (s *Stmt) invalidateSessionLocation() {
+	if mapping.PreparedStatementType(*s.preparedStmt) == mapping.StatementTypeSet {
+		s.conn.sessionLocation = nil
+	}
+}
+
 func argsToNamedArgs(values []driver.Value) []driver.NamedValue {
 	args := make([]driver.NamedValue, len(values))
 	for n, param := range values {
@@ -167,6 +167,14 @@ type (
 	}
 )
 
+// release releases the resources of the table source, if it is a releaser.
+// DuckDB destroys the bind data, and thereby releases the table source, after executing the table function.
+func (tfd tableFunctionData) release() {
+	if r, ok := tfd.fun.(releaser); ok {
+		r.release()
+	}
+}
+
 func isRowIdColumn(i mapping.IdxT) bool {
 	// FIXME: Replace this with mapping.IsRowIdColumn(i) / virtual column changes, once available in the C API.
 	return i == 18446744073709551615
@@ -323,6 +331,7 @@ func table_udf_row_callback(infoPtr unsafe.Pointer, outputPtr unsafe.Pointer) {
 		return
 	}
 
+	chunk.projection = instance.projection
 	row := Row{
 		chunk:      &chunk,
 		projection: instance.projection,
@@ -373,6 +382,8 @@ func table_udf_chunk_callback(infoPtr unsafe.Pointer, outputPtr unsafe.Pointer)
 		return
 	}
 
+	chunk.projection = instance.projection
+
 	switch fun := instance.fun.(type) {
 	case ChunkTableSource:
 		err = fun.FillChunk(chunk)
@@ -395,6 +406,13 @@ func table_udf_delete_callback(info unsafe.Pointer) {
 // RegisterTableUDF registers a user-defined table function.
 // Projection pushdown is enabled by default.
 func RegisterTableUDF[TFT TableFunction](conn *sql.Conn, name string, f TFT) error {
+	// Register the function on the underlying driver connection exposed by c.Raw.
+	return conn.Raw(func(driverConn any) error {
+		return registerTableUDF(driverConn.(*Conn), name, f)
+	})
+}
+
+func registerTableUDF[TFT TableFunction](c *Conn, name string, f TFT) error {
 	if name == "" {
 		return getError(errAPI, errTableUDFNoName)
 	}
@@ -510,15 +528,10 @@ func RegisterTableUDF[TFT TableFunction](conn *sql.Conn, name string, f TFT) err
 		mapping.DestroyLogicalType(&logicalType)
 	}
 
-	// Register the function on the underlying driver connection exposed by c.Raw.
-	err := conn.Raw(func(driverConn any) error {
-		c := driverConn.(*Conn)
-		state := mapping.RegisterTableFunction(c.conn, function)
-		mapping.DestroyTableFunction(&function)
-		if state == mapping.StateError {
-			return getError(errAPI, errTableUDFCreate)
-		}
-		return nil
-	})
-	return err
+	state := mapping.RegisterTableFunction(c.conn, function)
+	mapping.DestroyTableFunction(&function)
+	if state == mapping.StateError {
+		return getError(errAPI, errTableUDFCreate)
+	}
+	return nil
 }
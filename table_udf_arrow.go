@@ -0,0 +1,130 @@
+//go:build duckdb_arrow
+
+package duckdb
+
+import (
+	"database/sql"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// ArrowTableBindFunc binds the arguments of an Arrow-backed table function, and returns a reader of its records.
+type ArrowTableBindFunc func(named map[string]any, args ...any) (array.RecordReader, error)
+
+// RegisterArrowTableUDF registers a user-defined table function, which streams the records of an
+// Apache Arrow record reader. For each execution, it calls bind with the arguments of the table function.
+// The columns of the table function are the fields of the reader's schema, and the records must have
+// the reader's schema. The table function copies the projected columns of each record into its data chunks.
+// It releases the reader after the execution.
+func RegisterArrowTableUDF(conn *sql.Conn, name string, config TableFunctionConfig, bind ArrowTableBindFunc) error {
+	if bind == nil {
+		return getError(errAPI, errTableUDFMissingBindArgs)
+	}
+	return RegisterTableUDF(conn, name, ChunkTableFunction{
+		Config: config,
+		BindArguments: func(named map[string]any, args ...any) (ChunkTableSource, error) {
+			s := &arrowReaderSource{bind: bind, named: named, args: args}
+			if err := s.bindReader(); err != nil {
+				return nil, err
+			}
+			columns, err := arrowColumnInfos(s.schema)
+			if err != nil {
+				s.release()
+				return nil, err
+			}
+			s.columns = columns
+			return s, nil
+		},
+	})
+}
+
+// arrowReaderSource is a ChunkTableSource, which streams the records of an Arrow record reader.
+type arrowReaderSource struct {
+	bind  ArrowTableBindFunc
+	named map[string]any
+	args  []any
+
+	reader  array.RecordReader
+	schema  *arrow.Schema
+	columns []ColumnInfo
+	// True, if the reader has been scanned by an execution.
+	scanned bool
+
+	// The current record, and the offset of its next row.
+	rec    arrow.Record
+	offset int
+	err    error
+}
+
+// bindReader releases the current reader, and binds a new reader.
+func (s *arrowReaderSource) bindReader() error {
+	s.release()
+	reader, err := s.bind(s.named, s.args...)
+	if err != nil {
+		return err
+	}
+	if reader == nil {
+		return interfaceIsNilError("array.RecordReader")
+	}
+	s.reader = reader
+
+	if s.schema == nil {
+		s.schema = reader.Schema()
+	} else if !reader.Schema().Equal(s.schema) {
+		err = invalidInputError("another schema for the record reader of an execution", "the schema of the bound record reader")
+		return getError(errArrowSchemaMismatch, err)
+	}
+	return nil
+}
+
+func (s *arrowReaderSource) ColumnInfos() []ColumnInfo {
+	return s.columns
+}
+
+func (s *arrowReaderSource) Cardinality() *CardinalityInfo {
+	return nil
+}
+
+func (s *arrowReaderSource) Init() {
+	// A prepared statement executes the table function again with the same bind data.
+	if s.scanned {
+		s.err = s.bindReader()
+	}
+	s.scanned = true
+	s.rec = nil
+	s.offset = 0
+}
+
+func (s *arrowReaderSource) FillChunk(chunk DataChunk) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	// Get the next record with remaining rows.
+	for s.rec == nil || s.offset >= int(s.rec.NumRows()) {
+		if !s.reader.Next() {
+			if err := s.reader.Err(); err != nil {
+				return err
+			}
+			return chunk.SetSize(0)
+		}
+		s.rec = s.reader.Record()
+		s.offset = 0
+	}
+
+	n := min(int(s.rec.NumRows())-s.offset, GetDataChunkCapacity())
+	if err := fillArrowChunk(chunk, s.rec, s.offset, n); err != nil {
+		return err
+	}
+	s.offset += n
+	return nil
+}
+
+func (s *arrowReaderSource) release() {
+	if s.reader != nil {
+		s.reader.Release()
+		s.reader = nil
+		s.rec = nil
+	}
+}
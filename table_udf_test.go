@@ -567,7 +567,8 @@ func (udf *pushdownTableUDF) FillRow(row Row) (bool, error) {
 	if err := SetRowValue(row, 0, udf.count); err != nil {
 		return false, err
 	}
-	if err := SetRowValue(row, 1, udf.count); err != nil {
+	// The method maps the column like the function.
+	if err := row.SetRowValue(1, udf.count); err != nil {
 		return false, err
 	}
 	return true, nil
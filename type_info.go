@@ -119,11 +119,8 @@ func NewTypeInfo(t Type) (TypeInfo, error) {
 // NewDecimalInfo returns DECIMAL type information.
 // Its input parameters are the width and scale of the DECIMAL type.
 func NewDecimalInfo(width uint8, scale uint8) (TypeInfo, error) {
-	if width < 1 || width > max_decimal_width {
-		return nil, getError(errAPI, errInvalidDecimalWidth)
-	}
-	if scale > width {
-		return nil, getError(errAPI, errInvalidDecimalScale)
+	if err := validateDecimal(width, scale); err != nil {
+		return nil, err
 	}
 
 	return &typeInfo{
@@ -362,3 +359,86 @@ func destroyLogicalTypes(types *[]mapping.LogicalType) {
 		mapping.DestroyLogicalType(&t)
 	}
 }
+
+// newTypeInfoFromLogicalType returns the type information of a logical type.
+func newTypeInfoFromLogicalType(logicalType mapping.LogicalType) (TypeInfo, error) {
+	t := Type(mapping.GetTypeId(logicalType))
+	switch t {
+	case TYPE_DECIMAL:
+		return NewDecimalInfo(mapping.DecimalWidth(logicalType), mapping.DecimalScale(logicalType))
+	case TYPE_ENUM:
+		size := mapping.EnumDictionarySize(logicalType)
+		names := make([]string, size)
+		for i := range names {
+			names[i] = mapping.EnumDictionaryValue(logicalType, mapping.IdxT(i))
+		}
+		if len(names) == 0 {
+			return nil, getError(errAPI, errors.New("ENUM type must have at least one value"))
+		}
+		return NewEnumInfo(names[0], names[1:]...)
+	case TYPE_LIST:
+		child := mapping.ListTypeChildType(logicalType)
+		defer mapping.DestroyLogicalType(&child)
+		childInfo, err := newTypeInfoFromLogicalType(child)
+		if err != nil {
+			return nil, err
+		}
+		return NewListInfo(childInfo)
+	case TYPE_ARRAY:
+		child := mapping.ArrayTypeChildType(logicalType)
+		defer mapping.DestroyLogicalType(&child)
+		childInfo, err := newTypeInfoFromLogicalType(child)
+		if err != nil {
+			return nil, err
+		}
+		return NewArrayInfo(childInfo, uint64(mapping.ArrayTypeArraySize(logicalType)))
+	case TYPE_MAP:
+		key := mapping.MapTypeKeyType(logicalType)
+		defer mapping.DestroyLogicalType(&key)
+		value := mapping.MapTypeValueType(logicalType)
+		defer mapping.DestroyLogicalType(&value)
+		keyInfo, err := newTypeInfoFromLogicalType(key)
+		if err != nil {
+			return nil, err
+		}
+		valueInfo, err := newTypeInfoFromLogicalType(value)
+		if err != nil {
+			return nil, err
+		}
+		return NewMapInfo(keyInfo, valueInfo)
+	case TYPE_STRUCT:
+		count := mapping.StructTypeChildCount(logicalType)
+		entries := make([]StructEntry, count)
+		for i := mapping.IdxT(0); i < count; i++ {
+			child := mapping.StructTypeChildType(logicalType, i)
+			childInfo, err := newTypeInfoFromLogicalType(child)
+			mapping.DestroyLogicalType(&child)
+			if err != nil {
+				return nil, err
+			}
+			if entries[i], err = NewStructEntry(childInfo, mapping.StructTypeChildName(logicalType, i)); err != nil {
+				return nil, err
+			}
+		}
+		if len(entries) == 0 {
+			return nil, getError(errAPI, errors.New("STRUCT type must have at least one entry"))
+		}
+		return NewStructInfo(entries[0], entries[1:]...)
+	case TYPE_UNION:
+		count := mapping.UnionTypeMemberCount(logicalType)
+		memberTypes := make([]TypeInfo, count)
+		memberNames := make([]string, count)
+		for i := mapping.IdxT(0); i < count; i++ {
+			member := mapping.UnionTypeMemberType(logicalType, i)
+			memberInfo, err := newTypeInfoFromLogicalType(member)
+			mapping.DestroyLogicalType(&member)
+			if err != nil {
+				return nil, err
+			}
+			memberTypes[i] = memberInfo
+			memberNames[i] = mapping.UnionTypeMemberName(logicalType, i)
+		}
+		return NewUnionInfo(memberTypes, memberNames)
+	}
+	return NewTypeInfo(t)
+}
@@ -7,12 +7,10 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
-	"strings"
 	"time"
 
 	"github.com/marcboeker/go-duckdb/mapping"
 
-	"github.com/go-viper/mapstructure/v2"
 	"github.com/google/uuid"
 )
 
@@ -104,34 +102,93 @@ func hugeIntFromNative(i *big.Int) (*mapping.HugeInt, error) {
 type Map map[any]any
 
 func (m *Map) Scan(v any) error {
-	data, ok := v.(Map)
-	if !ok {
+	switch data := v.(type) {
+	case Map:
+		*m = data
+	case OrderedMap:
+		mapped, err := data.toMap()
+		if err != nil {
+			return err
+		}
+		*m = mapped
+	default:
 		return fmt.Errorf("invalid type `%T` for scanning `Map`, expected `Map`", data)
 	}
+	return nil
+}
+
+// MapEntry is a key-value pair of a MAP.
+type MapEntry struct {
+	Key   any `json:"key"`
+	Value any `json:"value"`
+}
 
-	*m = data
+// OrderedMap is a MAP representation that preserves the order of its entries.
+// Unlike Map, it supports keys that are not comparable in Go, e.g., LIST and STRUCT keys.
+// MAP values with such keys are always returned as an OrderedMap.
+// To return all MAP values as an OrderedMap, see Connector.UseOrderedMaps.
+type OrderedMap []MapEntry
+
+func (m *OrderedMap) Scan(v any) error {
+	switch data := v.(type) {
+	case OrderedMap:
+		*m = data
+	case Map:
+		// The entry order of a Map is undefined.
+		entries := make(OrderedMap, 0, len(data))
+		for key, value := range data {
+			entries = append(entries, MapEntry{Key: key, Value: value})
+		}
+		*m = entries
+	default:
+		return fmt.Errorf("invalid type `%T` for scanning `OrderedMap`, expected `OrderedMap`", data)
+	}
 	return nil
 }
 
-func mapKeysField() string {
-	return "key"
+func (m OrderedMap) toMap() (Map, error) {
+	mapped := make(Map, len(m))
+	for _, entry := range m {
+		if entry.Key != nil && !reflect.ValueOf(entry.Key).Comparable() {
+			return nil, getError(errUnsupportedMapKeyType, fmt.Errorf("cannot use `%T` as a `Map` key, use `OrderedMap` instead", entry.Key))
+		}
+		mapped[entry.Key] = entry.Value
+	}
+	return mapped, nil
 }
 
-func mapValuesField() string {
-	return "value"
+// TypedMap is a MAP with typed keys and values, e.g., TypedMap[string, int32] for a MAP(VARCHAR, INTEGER).
+// Use it as the `Scanner` type of a MAP, or to bind and append a MAP.
+// Like Composite, it decodes nested keys and values into their Go types.
+type TypedMap[K comparable, V any] map[K]V
+
+func (m *TypedMap[K, V]) Scan(v any) error {
+	var typed map[K]V
+	if err := decodeValue(v, &typed); err != nil {
+		return err
+	}
+	*m = typed
+	return nil
 }
 
-type Interval struct {
-	Days   int32 `json:"days"`
-	Months int32 `json:"months"`
-	Micros int64 `json:"micros"`
+func mapKeysField() string {
+	return "key"
 }
 
-func (i *Interval) getMappedInterval() *mapping.Interval {
-	return mapping.NewInterval(i.Months, i.Days, i.Micros)
+func mapValuesField() string {
+	return "value"
 }
 
-// Use as the `Scanner` type for any composite types (maps, lists, structs)
+// Use as the `Scanner` type for any composite types (maps, lists, structs).
+// STRUCT values decode into structs and maps, LIST and ARRAY values into slices and arrays,
+// and MAP values into maps.
+// A struct field decodes the STRUCT entry named by its `duckdb` tag, e.g., `duckdb:"user_id"`,
+// or, without a tag, the entry matching its name case-insensitively. The tag `duckdb:"-"` skips a field.
+// The fields of embedded structs are promoted, unless the embedded struct has a tag.
+// Without a `duckdb` tag, a field falls back to its `mapstructure` tag, including the `squash` option.
+// Unlike mapstructure, there are no weakly typed conversions, e.g., from a string to an int:
+// a value that does not fit the field's type returns an error, rather than decoding to the zero value.
+// Errors name the path of the failing field, e.g., "Items[2].Name".
 type Composite[T any] struct {
 	t T
 }
@@ -141,54 +198,12 @@ func (s Composite[T]) Get() T {
 }
 
 func (s *Composite[T]) Scan(v any) error {
-	return mapstructure.Decode(v, &s.t)
-}
-
-const max_decimal_width = 38
-
-type Decimal struct {
-	Width uint8
-	Scale uint8
-	Value *big.Int
-}
-
-func (d Decimal) Float64() float64 {
-	scale := big.NewInt(int64(d.Scale))
-	factor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), scale, nil))
-	value := new(big.Float).SetInt(d.Value)
-	value.Quo(value, factor)
-	f, _ := value.Float64()
-	return f
-}
-
-func (d Decimal) String() string {
-	// Get the sign, and return early, if zero.
-	if d.Value.Sign() == 0 {
-		return "0"
-	}
-
-	// Remove the sign from the string integer value
-	var signStr string
-	scaleless := d.Value.String()
-	if d.Value.Sign() < 0 {
-		signStr = "-"
-		scaleless = scaleless[1:]
+	var t T
+	if err := decodeValue(v, &t); err != nil {
+		return err
 	}
-
-	// Remove all zeros from the right side
-	zeroTrimmed := strings.TrimRightFunc(scaleless, func(r rune) bool { return r == '0' })
-	scale := int(d.Scale) - (len(scaleless) - len(zeroTrimmed))
-
-	// If the string is still bigger than the scale factor, output it without a decimal point
-	if scale <= 0 {
-		return signStr + zeroTrimmed + strings.Repeat("0", -1*scale)
-	}
-
-	// Pad a number with 0.0's if needed
-	if len(zeroTrimmed) <= scale {
-		return fmt.Sprintf("%s0.%s%s", signStr, strings.Repeat("0", scale-len(zeroTrimmed)), zeroTrimmed)
-	}
-	return signStr + zeroTrimmed[:len(zeroTrimmed)-scale] + "." + zeroTrimmed[len(zeroTrimmed)-scale:]
+	s.t = t
+	return nil
 }
 
 type Union struct {
@@ -334,262 +334,6 @@ func compareDecimal(t *testing.T, want Decimal, got Decimal) {
 	require.Equal(t, want.Value.String(), got.Value.String())
 }
 
-func TestDecimal(t *testing.T) {
-	db := openDbWrapper(t, ``)
-	defer closeDbWrapper(t, db)
-
-	t.Run("SELECT all possible DECIMAL widths", func(t *testing.T) {
-		for i := 1; i <= 38; i++ {
-			r := db.QueryRow(fmt.Sprintf(`SELECT 0::DECIMAL(%d, 1)`, i))
-			var actual Decimal
-			require.NoError(t, r.Scan(&actual))
-			expected := Decimal{Width: uint8(i), Value: big.NewInt(0), Scale: 1}
-			require.Equal(t, expected, actual)
-		}
-	})
-
-	t.Run("SELECT different DECIMAL types", func(t *testing.T) {
-		res, err := db.Query(`SELECT * FROM (VALUES
-			(1.23::DECIMAL(3, 2)),
-			(-1.23::DECIMAL(3, 2)),
-			(123.45::DECIMAL(5, 2)),
-			(-123.45::DECIMAL(5, 2)),
-			(123456789.01::DECIMAL(11, 2)),
-			(-123456789.01::DECIMAL(11, 2)),
-			(1234567890123456789.234::DECIMAL(22, 3)),
-			(-1234567890123456789.234::DECIMAL(22, 3)),
-		) v
-		ORDER BY v ASC`)
-		require.NoError(t, err)
-		defer closeRowsWrapper(t, res)
-
-		bigNumber, success := new(big.Int).SetString("1234567890123456789234", 10)
-		require.True(t, success)
-		bigNegativeNumber, success := new(big.Int).SetString("-1234567890123456789234", 10)
-		require.True(t, success)
-		tests := []struct {
-			input string
-			want  Decimal
-		}{
-			{input: "1.23::DECIMAL(3, 2)", want: Decimal{Value: big.NewInt(123), Width: 3, Scale: 2}},
-			{input: "-1.23::DECIMAL(3, 2)", want: Decimal{Value: big.NewInt(-123), Width: 3, Scale: 2}},
-			{input: "123.45::DECIMAL(5, 2)", want: Decimal{Value: big.NewInt(12345), Width: 5, Scale: 2}},
-			{input: "-123.45::DECIMAL(5, 2)", want: Decimal{Value: big.NewInt(-12345), Width: 5, Scale: 2}},
-			{input: "123456789.01::DECIMAL(11, 2)", want: Decimal{Value: big.NewInt(12345678901), Width: 11, Scale: 2}},
-			{input: "-123456789.01::DECIMAL(11, 2)", want: Decimal{Value: big.NewInt(-12345678901), Width: 11, Scale: 2}},
-			{input: "1234567890123456789.234::DECIMAL(22, 3)", want: Decimal{Value: bigNumber, Width: 22, Scale: 3}},
-			{input: "-1234567890123456789.234::DECIMAL(22, 3)", want: Decimal{Value: bigNegativeNumber, Width: 22, Scale: 3}},
-		}
-		for _, test := range tests {
-			r := db.QueryRow(fmt.Sprintf(`SELECT %s`, test.input))
-			var fs Decimal
-			require.NoError(t, r.Scan(&fs))
-			compareDecimal(t, test.want, fs)
-		}
-	})
-
-	t.Run("SELECT a huge DECIMAL ", func(t *testing.T) {
-		bigInt, success := new(big.Int).SetString("12345678901234567890123456789", 10)
-		require.True(t, success)
-		var f Decimal
-		require.NoError(t, db.QueryRow("SELECT 123456789.01234567890123456789::DECIMAL(29, 20)").Scan(&f))
-		compareDecimal(t, Decimal{Value: bigInt, Width: 29, Scale: 20}, f)
-	})
-
-	t.Run("SELECT DECIMAL types and compare them to FLOAT64", func(t *testing.T) {
-		tests := []struct {
-			input string
-			want  float64
-		}{
-			{input: "1.23::DECIMAL(3, 2)", want: 1.23},
-			{input: "-1.23::DECIMAL(3, 2)", want: -1.23},
-			{input: "123.45::DECIMAL(5, 2)", want: 123.45},
-			{input: "-123.45::DECIMAL(5, 2)", want: -123.45},
-			{input: "123456789.01::DECIMAL(11, 2)", want: 123456789.01},
-			{input: "-123456789.01::DECIMAL(11, 2)", want: -123456789.01},
-			{input: "1234567890123456789.234::DECIMAL(22, 3)", want: 1234567890123456789.234},
-			{input: "-1234567890123456789.234::DECIMAL(22, 3)", want: -1234567890123456789.234},
-			{input: "123456789.01234567890123456789::DECIMAL(29, 20)", want: 123456789.01234567890123456789},
-			{input: "-123456789.01234567890123456789::DECIMAL(29, 20)", want: -123456789.01234567890123456789},
-		}
-		for _, test := range tests {
-			r := db.QueryRow(fmt.Sprintf("SELECT %s", test.input))
-			var fs Decimal
-			require.NoError(t, r.Scan(&fs))
-			require.Equal(t, test.want, fs.Float64())
-		}
-	})
-
-	t.Run("SELECT DECIMAL types and compare them to STRING", func(t *testing.T) {
-		tests := []struct {
-			input string
-			want  string
-		}{
-			{input: "1.23::DECIMAL(3, 2)", want: "1.23"},
-			{input: "-1.23::DECIMAL(3, 2)", want: "-1.23"},
-			{input: "123.45::DECIMAL(5, 2)", want: "123.45"},
-			{input: "-123.45::DECIMAL(5, 2)", want: "-123.45"},
-			{input: "123456789.01::DECIMAL(11, 2)", want: "123456789.01"},
-			{input: "-123456789.01::DECIMAL(11, 2)", want: "-123456789.01"},
-			{input: "1234567890123456789.234::DECIMAL(22, 3)", want: "1234567890123456789.234"},
-			{input: "-1234567890123456789.234::DECIMAL(22, 3)", want: "-1234567890123456789.234"},
-			{input: "123456789.01234567890123456789::DECIMAL(29, 20)", want: "123456789.01234567890123456789"},
-			{input: "-123456789.01234567890123456789::DECIMAL(29, 20)", want: "-123456789.01234567890123456789"},
-		}
-		for _, test := range tests {
-			r := db.QueryRow(fmt.Sprintf("SELECT %s", test.input))
-			var fs Decimal
-			require.NoError(t, r.Scan(&fs))
-			require.Equal(t, test.want, fs.String())
-			// confirms Decimal implements fmt.Stringer correctly (see #424)
-			require.Equal(t, test.want, fmt.Sprint(fs))
-		}
-	})
-}
-
-func TestDecimalString(t *testing.T) {
-	testCases := []struct {
-		input    Decimal
-		expected string
-	}{
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 0,
-				Value: big.NewInt(0),
-			},
-			expected: "0",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 6,
-				Value: big.NewInt(0),
-			},
-			expected: "0",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 0,
-				Value: big.NewInt(1234567890),
-			},
-			expected: "1234567890",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 0,
-				Value: big.NewInt(-1234567890),
-			},
-			expected: "-1234567890",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 1,
-				Value: big.NewInt(1234567890),
-			},
-			expected: "123456789",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 1,
-				Value: big.NewInt(-1234567890),
-			},
-			expected: "-123456789",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 2,
-				Value: big.NewInt(1234567890),
-			},
-			expected: "12345678.9",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 2,
-				Value: big.NewInt(-1234567890),
-			},
-			expected: "-12345678.9",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 6,
-				Value: big.NewInt(1234567890),
-			},
-			expected: "1234.56789",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 6,
-				Value: big.NewInt(-1234567890),
-			},
-			expected: "-1234.56789",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 12,
-				Value: big.NewInt(1234567890),
-			},
-			expected: "0.00123456789",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 12,
-				Value: big.NewInt(-1234567890),
-			},
-			expected: "-0.00123456789",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 1,
-				Value: big.NewInt(1234500000),
-			},
-			expected: "123450000",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 1,
-				Value: big.NewInt(-1234500000),
-			},
-			expected: "-123450000",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 8,
-				Value: big.NewInt(-705399),
-			},
-			expected: "-0.00705399",
-		},
-		{
-			input: Decimal{
-				Width: 18,
-				Scale: 8,
-				Value: big.NewInt(821662),
-			},
-			expected: "0.00821662",
-		},
-	}
-
-	for _, tc := range testCases {
-		actual := tc.input.String()
-		if actual != tc.expected {
-			require.Equal(t, tc.expected, actual)
-		}
-	}
-}
-
 func TestBlob(t *testing.T) {
 	db := openDbWrapper(t, ``)
 	defer closeDbWrapper(t, db)
@@ -751,6 +495,105 @@ func TestENUMs(t *testing.T) {
 	require.ElementsMatch(t, []environment{Air, Sea, Land}, row.Get())
 }
 
+func TestOrderedMap(t *testing.T) {
+	t.Run("non-comparable keys", func(t *testing.T) {
+		db := openDbWrapper(t, ``)
+		defer closeDbWrapper(t, db)
+
+		var res any
+		require.NoError(t, db.QueryRow(`SELECT MAP([[3], [1, 2]], ['a', 'b'])`).Scan(&res))
+		require.Equal(t, OrderedMap{
+			{Key: []any{int32(3)}, Value: "a"},
+			{Key: []any{int32(1), int32(2)}, Value: "b"},
+		}, res)
+
+		var m OrderedMap
+		require.NoError(t, db.QueryRow(`SELECT MAP([{'x': 1}], [NULL])`).Scan(&m))
+		require.Equal(t, OrderedMap{{Key: map[string]any{"x": int32(1)}, Value: nil}}, m)
+
+		rows, err := db.Query(`SELECT MAP([[1]], [1]) AS m1, MAP([1], [1]) AS m2`)
+		require.NoError(t, err)
+		defer closeRowsWrapper(t, rows)
+		types, err := rows.ColumnTypes()
+		require.NoError(t, err)
+		require.Equal(t, reflect.TypeOf(OrderedMap{}), types[0].ScanType())
+		require.Equal(t, reflect.TypeOf(Map{}), types[1].ScanType())
+	})
+
+	t.Run("connector option", func(t *testing.T) {
+		c := newConnectorWrapper(t, ``, nil)
+		defer closeConnectorWrapper(t, c)
+		c.UseOrderedMaps()
+
+		db := sql.OpenDB(c)
+		defer closeDbWrapper(t, db)
+
+		var m OrderedMap
+		require.NoError(t, db.QueryRow(`SELECT MAP(['z', 'a', 'm'], [1, 2, 3])`).Scan(&m))
+		require.Equal(t, OrderedMap{{"z", int32(1)}, {"a", int32(2)}, {"m", int32(3)}}, m)
+
+		// Nested MAP values.
+		var list []any
+		require.NoError(t, db.QueryRow(`SELECT [MAP(['b', 'a'], [1, 2])]`).Scan(&list))
+		require.Equal(t, OrderedMap{{"b", int32(1)}, {"a", int32(2)}}, list[0])
+
+		// Scanning into a Map still works.
+		var unordered Map
+		require.NoError(t, db.QueryRow(`SELECT MAP(['z', 'a'], [1, 2])`).Scan(&unordered))
+		require.Equal(t, Map{"z": int32(1), "a": int32(2)}, unordered)
+	})
+
+	t.Run("DSN option", func(t *testing.T) {
+		db := openDbWrapper(t, `?ordered_maps=true`)
+		defer closeDbWrapper(t, db)
+
+		var res any
+		require.NoError(t, db.QueryRow(`SELECT MAP(['z', 'a'], [1, 2])`).Scan(&res))
+		require.Equal(t, OrderedMap{{"z", int32(1)}, {"a", int32(2)}}, res)
+
+		_, err := NewConnector(`?ordered_maps=maybe`, nil)
+		require.ErrorIs(t, err, errParseDSN)
+	})
+
+	t.Run("bind", func(t *testing.T) {
+		db := openDbWrapper(t, ``)
+		defer closeDbWrapper(t, db)
+
+		_, err := db.Exec(`CREATE TABLE test (id INT, m MAP(INTEGER[], VARCHAR), typed MAP(VARCHAR, INTEGER))`)
+		require.NoError(t, err)
+
+		m := OrderedMap{{Key: []int32{2}, Value: "b"}, {Key: []int32{1}, Value: nil}}
+		_, err = db.Exec(`INSERT INTO test VALUES (1, ?, ?)`, m, Map{"a": int32(1)})
+		require.NoError(t, err)
+		_, err = db.Exec(`INSERT INTO test VALUES (2, NULL, ?)`, TypedMap[string, int32]{"b": 2})
+		require.NoError(t, err)
+
+		var mStr, typedStr string
+		require.NoError(t, db.QueryRow(`SELECT m::VARCHAR, typed::VARCHAR FROM test WHERE id = 1`).Scan(&mStr, &typedStr))
+		require.Equal(t, `{[2]=b, [1]=NULL}`, mStr)
+		require.Equal(t, `{a=1}`, typedStr)
+
+		require.NoError(t, db.QueryRow(`SELECT typed::VARCHAR FROM test WHERE id = 2`).Scan(&typedStr))
+		require.Equal(t, `{b=2}`, typedStr)
+	})
+}
+
+func TestTypedMap(t *testing.T) {
+	db := openDbWrapper(t, ``)
+	defer closeDbWrapper(t, db)
+
+	var m TypedMap[string, int32]
+	require.NoError(t, db.QueryRow(`SELECT MAP(['a', 'b'], [1, NULL])`).Scan(&m))
+	require.Equal(t, TypedMap[string, int32]{"a": 1, "b": 0}, m)
+
+	var lists TypedMap[int64, []string]
+	require.NoError(t, db.QueryRow(`SELECT MAP([1::BIGINT], [['x', 'y']])`).Scan(&lists))
+	require.Equal(t, TypedMap[int64, []string]{1: {"x", "y"}}, lists)
+
+	err := db.QueryRow(`SELECT MAP([[1]], [1])`).Scan(&m)
+	require.ErrorContains(t, err, castErrMsg)
+}
+
 func TestHugeInt(t *testing.T) {
 	db := openDbWrapper(t, ``)
 	defer closeDbWrapper(t, db)
@@ -818,6 +661,101 @@ func TestTimestampTZ(t *testing.T) {
 	require.Equal(t, ts.UTC(), tz)
 }
 
+func TestTimestampTZLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	ts := time.Date(2024, time.July, 1, 12, 30, 0, 0, time.UTC)
+
+	t.Run("connector location", func(t *testing.T) {
+		c := newConnectorWrapper(t, ``, nil)
+		defer closeConnectorWrapper(t, c)
+		c.SetTimestampTZLocation(berlin)
+
+		db := sql.OpenDB(c)
+		defer closeDbWrapper(t, db)
+
+		var res time.Time
+		require.NoError(t, db.QueryRow(`SELECT ?::TIMESTAMPTZ`, ts).Scan(&res))
+		require.True(t, ts.Equal(res))
+		require.Equal(t, berlin, res.Location())
+
+		// TIMESTAMP values are unaffected.
+		require.NoError(t, db.QueryRow(`SELECT ?::TIMESTAMP`, ts).Scan(&res))
+		require.Equal(t, ts, res)
+
+		// Nested TIMESTAMPTZ values.
+		var list []any
+		require.NoError(t, db.QueryRow(`SELECT [?::TIMESTAMPTZ]`, ts).Scan(&list))
+		require.Equal(t, berlin, list[0].(time.Time).Location())
+	})
+
+	t.Run("DSN location", func(t *testing.T) {
+		db := openDbWrapper(t, `?timestamptz_location=Europe/Berlin`)
+		defer closeDbWrapper(t, db)
+
+		var res time.Time
+		require.NoError(t, db.QueryRow(`SELECT ?::TIMESTAMPTZ`, ts).Scan(&res))
+		require.True(t, ts.Equal(res))
+		require.Equal(t, berlin, res.Location())
+	})
+
+	t.Run("bound location", func(t *testing.T) {
+		db := openDbWrapper(t, ``)
+		defer closeDbWrapper(t, db)
+
+		_, err := db.Exec(`CREATE TABLE tbl (ts TIMESTAMP, tz TIMESTAMPTZ)`)
+		require.NoError(t, err)
+		_, err = db.Exec(`INSERT INTO tbl VALUES (?, ?)`, ts.In(berlin), ts.In(berlin))
+		require.NoError(t, err)
+
+		// A TIMESTAMP gets the wall clock, and a TIMESTAMPTZ gets the instant.
+		var wallClock, instant time.Time
+		require.NoError(t, db.QueryRow(`SELECT ts, tz FROM tbl`).Scan(&wallClock, &instant))
+		require.Equal(t, time.Date(2024, time.July, 1, 14, 30, 0, 0, time.UTC), wallClock)
+		require.True(t, ts.Equal(instant))
+
+		var res time.Time
+		require.NoError(t, db.QueryRow(`SELECT ?::TIMESTAMP_MS`, ts.In(berlin)).Scan(&res))
+		require.Equal(t, wallClock, res)
+	})
+
+	t.Run("invalid DSN location", func(t *testing.T) {
+		_, err := NewConnector(`?timestamptz_location=Not/AZone`, nil)
+		require.ErrorIs(t, err, errParseDSN)
+	})
+
+	t.Run("session time zone", func(t *testing.T) {
+		db := openDbWrapper(t, `?timestamptz_location=session`)
+		defer closeDbWrapper(t, db)
+		db.SetMaxOpenConns(1)
+
+		_, err := db.Exec(`CREATE TABLE tbl (tz TIMESTAMPTZ)`)
+		require.NoError(t, err)
+
+		_, err = db.Exec(`SET TimeZone = 'Europe/Berlin'`)
+		require.NoError(t, err)
+
+		// Binding a non-UTC time.Time to a TIMESTAMPTZ parameter preserves the instant.
+		_, err = db.Exec(`INSERT INTO tbl VALUES (?)`, ts.In(berlin))
+		require.NoError(t, err)
+
+		var res time.Time
+		require.NoError(t, db.QueryRow(`SELECT tz FROM tbl`).Scan(&res))
+		require.True(t, ts.Equal(res))
+		require.Equal(t, berlin, res.Location())
+
+		var str string
+		require.NoError(t, db.QueryRow(`SELECT tz::VARCHAR FROM tbl`).Scan(&str))
+		require.Equal(t, "2024-07-01 14:30:00+02", str)
+
+		_, err = db.Exec(`SET TimeZone = 'UTC'`)
+		require.NoError(t, err)
+		require.NoError(t, db.QueryRow(`SELECT tz FROM tbl`).Scan(&res))
+		require.True(t, ts.Equal(res))
+		require.Equal(t, "UTC", res.Location().String())
+	})
+}
+
 func TestBoolean(t *testing.T) {
 	db := openDbWrapper(t, ``)
 	defer closeDbWrapper(t, db)
@@ -858,46 +796,6 @@ func TestTimestamp(t *testing.T) {
 	}
 }
 
-func TestInterval(t *testing.T) {
-	db := openDbWrapper(t, ``)
-	defer closeDbWrapper(t, db)
-
-	t.Run("INTERVAL binding", func(t *testing.T) {
-		interval := Interval{Days: 10, Months: 4, Micros: 4}
-		row := db.QueryRow("SELECT ?::INTERVAL", interval)
-
-		var res Interval
-		require.NoError(t, row.Scan(&res))
-		require.Equal(t, interval, res)
-	})
-
-	t.Run("INTERVAL scanning", func(t *testing.T) {
-		tests := map[string]struct {
-			input string
-			want  Interval
-		}{
-			"simple interval": {
-				input: "INTERVAL 5 HOUR",
-				want:  Interval{Days: 0, Months: 0, Micros: 18000000000},
-			},
-			"interval arithmetic": {
-				input: "INTERVAL 1 DAY + INTERVAL 5 DAY",
-				want:  Interval{Days: 6, Months: 0, Micros: 0},
-			},
-			"timestamp arithmetic": {
-				input: "CAST('2022-05-01' as TIMESTAMP) - CAST('2022-04-01' as TIMESTAMP)",
-				want:  Interval{Days: 30, Months: 0, Micros: 0},
-			},
-		}
-		for _, test := range tests {
-			var res Interval
-			err := db.QueryRow(fmt.Sprintf("SELECT %s", test.input)).Scan(&res)
-			require.NoError(t, err)
-			require.Equal(t, test.want, res)
-		}
-	})
-}
-
 func TestArray(t *testing.T) {
 	db := openDbWrapper(t, ``)
 	defer closeDbWrapper(t, db)
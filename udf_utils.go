@@ -17,8 +17,16 @@ type unpinner interface {
 	unpin()
 }
 
+// releaser is a pinned value, which releases its resources when it is unpinned.
+type releaser interface {
+	release()
+}
+
 func (v pinnedValue[T]) unpin() {
 	v.pinner.Unpin()
+	if r, ok := any(v.value).(releaser); ok {
+		r.release()
+	}
 }
 
 func getPinned[T any](handle unsafe.Pointer) T {
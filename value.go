@@ -1,6 +1,7 @@
 package duckdb
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 
@@ -101,6 +102,8 @@ func createValue(lt mapping.LogicalType, v any) (*mapping.Value, error) {
 		return getMappedSliceValue(lt, t, v)
 	case TYPE_STRUCT:
 		return getMappedStructValue(lt, v)
+	case TYPE_MAP:
+		return getMappedMapValue(lt, v)
 	default:
 		return nil, unsupportedTypeError(reflect.TypeOf(v).Name())
 	}
@@ -174,12 +177,82 @@ func getMappedStructValue(lt mapping.LogicalType, val any) (*mapping.Value, erro
 	return &structValue, nil
 }
 
+func getMappedMapValue(lt mapping.LogicalType, val any) (*mapping.Value, error) {
+	entries, err := extractMapEntries(val)
+	if err != nil {
+		return nil, err
+	}
+
+	keyType := mapping.MapTypeKeyType(lt)
+	defer mapping.DestroyLogicalType(&keyType)
+	valueType := mapping.MapTypeValueType(lt)
+	defer mapping.DestroyLogicalType(&valueType)
+
+	keys := make([]mapping.Value, 0, len(entries))
+	values := make([]mapping.Value, 0, len(entries))
+	defer func() {
+		destroyValueSlice(keys)
+		destroyValueSlice(values)
+	}()
+
+	for _, entry := range entries {
+		if entry.Key == nil {
+			return nil, errors.New("could not create value: MAP keys cannot be NULL")
+		}
+		k, err := createValue(keyType, entry.Key)
+		if err != nil {
+			return nil, fmt.Errorf("could not create value %s", err)
+		}
+		keys = append(keys, *k)
+
+		if entry.Value == nil {
+			values = append(values, mapping.CreateNullValue())
+			continue
+		}
+		v, err := createValue(valueType, entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("could not create value %s", err)
+		}
+		values = append(values, *v)
+	}
+
+	mapValue := mapping.CreateMapValue(lt, keys, values)
+	return &mapValue, nil
+}
+
 func destroyValueSlice(values []mapping.Value) {
 	for _, v := range values {
 		mapping.DestroyValue(&v)
 	}
 }
 
+// extractMapEntries returns the entries of a Map, an OrderedMap, or any other Go map, e.g., a TypedMap.
+func extractMapEntries[S any](val S) (OrderedMap, error) {
+	switch v := any(val).(type) {
+	case OrderedMap:
+		return v, nil
+	case Map:
+		var m OrderedMap
+		_ = m.Scan(v)
+		return m, nil
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Map {
+		return nil, castError(reflect.TypeOf(val).String(), reflect.TypeOf(OrderedMap{}).String())
+	}
+	m := make(OrderedMap, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		entry := MapEntry{Key: iter.Key().Interface()}
+		if value := iter.Value(); !canNil(value) || !value.IsNil() {
+			entry.Value = value.Interface()
+		}
+		m = append(m, entry)
+	}
+	return m, nil
+}
+
 func canNil(val reflect.Value) bool {
 	switch val.Kind() {
 	case reflect.Chan, reflect.Func, reflect.Map, reflect.Pointer,
@@ -1,6 +1,7 @@
 package duckdb
 
 import (
+	"time"
 	"unsafe"
 
 	"github.com/marcboeker/go-duckdb/mapping"
@@ -20,6 +21,12 @@ type vector struct {
 	setFn fnSetVectorValue
 	// The child vectors of nested data types.
 	childVectors []vector
+	// The location of TIMESTAMPTZ values. If nil, they are in UTC.
+	tzLocation *time.Location
+	// True, if the vector returns its MAP values as an OrderedMap.
+	orderedMap bool
+	// True, if the vector holds JSON values.
+	json bool
 
 	// The vector's type information.
 	vectorTypeInfo
@@ -121,6 +128,36 @@ func (vec *vector) initVectors(v mapping.Vector, writable bool) {
 	vec.initChildVectors(v, writable)
 }
 
+func (vec *vector) hasTimestampTZ() bool {
+	if vec.Type == TYPE_TIMESTAMP_TZ {
+		return true
+	}
+	for i := range vec.childVectors {
+		if vec.childVectors[i].hasTimestampTZ() {
+			return true
+		}
+	}
+	return false
+}
+
+func (vec *vector) setTimestampTZLocation(loc *time.Location) {
+	if vec.Type == TYPE_TIMESTAMP_TZ {
+		vec.tzLocation = loc
+	}
+	for i := range vec.childVectors {
+		vec.childVectors[i].setTimestampTZLocation(loc)
+	}
+}
+
+func (vec *vector) setOrderedMaps() {
+	if vec.Type == TYPE_MAP {
+		vec.orderedMap = true
+	}
+	for i := range vec.childVectors {
+		vec.childVectors[i].setOrderedMaps()
+	}
+}
+
 func (vec *vector) initChildVectors(v mapping.Vector, writable bool) {
 	switch vec.Type {
 	case TYPE_LIST, TYPE_MAP:
@@ -288,6 +325,7 @@ func (vec *vector) initJSON() {
 		return setJSON(vec, rowIdx, val)
 	}
 	vec.Type = TYPE_VARCHAR
+	vec.json = true
 }
 
 func (vec *vector) initDecimal(logicalType mapping.LogicalType, colIdx int) error {
@@ -440,20 +478,16 @@ func (vec *vector) initMap(logicalType mapping.LogicalType, colIdx int) error {
 	}
 
 	// DuckDB supports more MAP key types than Go, which only supports comparable types.
-	// We ensure that the key type itself is comparable.
-	keyType := mapping.MapTypeKeyType(logicalType)
-	defer mapping.DestroyLogicalType(&keyType)
-
-	t := Type(mapping.GetTypeId(keyType))
-	switch t {
-	case TYPE_LIST, TYPE_STRUCT, TYPE_MAP, TYPE_ARRAY, TYPE_UNION:
-		return addIndexToError(errUnsupportedMapKeyType, colIdx)
-	}
+	// We return MAP values with other key types as an OrderedMap.
+	vec.orderedMap = !mapKeyIsComparable(logicalType)
 
 	vec.getFn = func(vec *vector, rowIdx mapping.IdxT) any {
 		if vec.getNull(rowIdx) {
 			return nil
 		}
+		if vec.orderedMap {
+			return vec.getOrderedMap(rowIdx)
+		}
 		return vec.getMap(rowIdx)
 	}
 	vec.setFn = func(vec *vector, rowIdx mapping.IdxT, val any) error {
@@ -467,6 +501,18 @@ func (vec *vector) initMap(logicalType mapping.LogicalType, colIdx int) error {
 	return nil
 }
 
+func mapKeyIsComparable(logicalType mapping.LogicalType) bool {
+	keyType := mapping.MapTypeKeyType(logicalType)
+	defer mapping.DestroyLogicalType(&keyType)
+
+	t := Type(mapping.GetTypeId(keyType))
+	switch t {
+	case TYPE_LIST, TYPE_STRUCT, TYPE_MAP, TYPE_ARRAY, TYPE_UNION:
+		return false
+	}
+	return true
+}
+
 func (vec *vector) initArray(logicalType mapping.LogicalType, colIdx int) error {
 	vec.arrayLength = mapping.ArrayTypeArraySize(logicalType)
 
@@ -25,8 +25,14 @@ func getPrimitive[T any](vec *vector, rowIdx mapping.IdxT) T {
 
 func (vec *vector) getTS(t Type, rowIdx mapping.IdxT) time.Time {
 	switch t {
-	case TYPE_TIMESTAMP, TYPE_TIMESTAMP_TZ:
+	case TYPE_TIMESTAMP:
+		val := getPrimitive[mapping.Timestamp](vec, rowIdx)
+		return getTS(t, &val)
+	case TYPE_TIMESTAMP_TZ:
 		val := getPrimitive[mapping.Timestamp](vec, rowIdx)
+		if vec.tzLocation != nil {
+			return getTS(t, &val).In(vec.tzLocation)
+		}
 		return getTS(t, &val)
 	case TYPE_TIMESTAMP_S:
 		val := getPrimitive[mapping.TimestampS](vec, rowIdx)
@@ -203,6 +209,17 @@ func (vec *vector) getMap(rowIdx mapping.IdxT) Map {
 	return m
 }
 
+func (vec *vector) getOrderedMap(rowIdx mapping.IdxT) OrderedMap {
+	list := vec.getList(rowIdx)
+
+	m := make(OrderedMap, len(list))
+	for i := 0; i < len(list); i++ {
+		mapItem := list[i].(map[string]any)
+		m[i] = MapEntry{Key: mapItem[mapKeysField()], Value: mapItem[mapValuesField()]}
+	}
+	return m
+}
+
 func (vec *vector) getArray(rowIdx mapping.IdxT) []any {
 	length := uint64(vec.arrayLength)
 	return vec.getSliceChild(uint64(rowIdx)*length, length)
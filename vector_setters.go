@@ -2,6 +2,7 @@ package duckdb
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/big"
 	"reflect"
 	"strconv"
@@ -24,6 +25,22 @@ func (vec *vector) setNull(rowIdx mapping.IdxT) {
 	}
 }
 
+// setValid marks a row as valid, including the child rows that belong to it.
+// LIST and MAP children are appended, and not reused, so they are not reset.
+func (vec *vector) setValid(rowIdx mapping.IdxT) {
+	mapping.ValiditySetRowValid(vec.maskPtr, rowIdx)
+	switch vec.Type {
+	case TYPE_STRUCT, TYPE_UNION:
+		for i := 0; i < len(vec.childVectors); i++ {
+			vec.childVectors[i].setValid(rowIdx)
+		}
+	case TYPE_ARRAY:
+		for i := mapping.IdxT(0); i < vec.arrayLength; i++ {
+			vec.childVectors[0].setValid(rowIdx*vec.arrayLength + i)
+		}
+	}
+}
+
 func setPrimitive[T any](vec *vector, rowIdx mapping.IdxT, v T) {
 	xs := (*[1 << 31]T)(vec.dataPtr)
 	xs[rowIdx] = v
@@ -238,6 +255,15 @@ func setJSON[S any](vec *vector, rowIdx mapping.IdxT, val S) error {
 }
 
 func setDecimal[S any](vec *vector, rowIdx mapping.IdxT, val S) error {
+	// Convert a DECIMAL with a different scale to the scale of the column.
+	if v, ok := any(val).(Decimal); ok && v.Value != nil && v.Scale != vec.decimalScale {
+		d, err := v.Rescale(vec.decimalWidth, vec.decimalScale)
+		if err != nil {
+			return castError(v.String(), fmt.Sprintf("DECIMAL(%d,%d)", vec.decimalWidth, vec.decimalScale))
+		}
+		return setDecimal[Decimal](vec, rowIdx, d)
+	}
+
 	switch vec.internalType {
 	case TYPE_SMALLINT:
 		return setNumeric[S, int16](vec, rowIdx, val)
@@ -342,20 +368,15 @@ func setStruct[S any](vec *vector, rowIdx mapping.IdxT, val S) error {
 }
 
 func setMap[S any](vec *vector, rowIdx mapping.IdxT, val S) error {
-	var m Map
-	switch v := any(val).(type) {
-	case Map:
-		m = v
-	default:
-		return castError(reflect.TypeOf(val).String(), reflect.TypeOf(m).String())
+	m, err := extractMapEntries(val)
+	if err != nil {
+		return err
 	}
 
 	// Create a LIST of STRUCT values.
-	i := 0
 	list := make([]any, len(m))
-	for key, value := range m {
-		list[i] = map[string]any{mapKeysField(): key, mapValuesField(): value}
-		i++
+	for i, entry := range m {
+		list[i] = map[string]any{mapKeysField(): entry.Key, mapValuesField(): entry.Value}
 	}
 
 	return setList(vec, rowIdx, list)